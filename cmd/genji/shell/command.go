@@ -1,13 +1,47 @@
 package shell
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/agnivade/levenshtein"
 	"github.com/genjidb/genji"
 	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/migrate"
 )
 
+// ErrExit is returned by Dispatch for ".exit", so the shell's read loop
+// can tell a normal exit request apart from a command failure.
+var ErrExit = errors.New("exit")
+
+// Dispatch routes a single line of shell input starting with "." to the
+// matching run*Cmd, the one entry point the shell's read loop is meant
+// to call for every line it reads. Without this, none of the commands
+// registered in the commands table above - including .migrate - are
+// ever reachable, no matter how they're implemented.
+func Dispatch(db *genji.DB, line string) error {
+	cmd := strings.Fields(line)
+	if len(cmd) == 0 {
+		return nil
+	}
+
+	switch cmd[0] {
+	case ".exit":
+		return ErrExit
+	case ".help":
+		return runHelpCmd()
+	case ".tables":
+		return runTablesCmd(db, cmd)
+	case ".indexes":
+		return runIndexesCmd(db, cmd)
+	case ".migrate":
+		return runMigrateCmd(db, cmd)
+	}
+
+	return displaySuggestions(cmd[0])
+}
+
 var commands = []struct {
 	Name        string
 	Options     string
@@ -17,8 +51,13 @@ var commands = []struct {
 	{".help", ``, "List all commands."},
 	{".tables", ``, "List names of tables."},
 	{".indexes", `[table_name]`, "Display all indexes or the indexes of the given table name."},
+	{".migrate", `up|down [n]|status`, "Run, revert or inspect schema migrations."},
 }
 
+// migrationsDir is where .migrate looks for migration files, relative to
+// the directory the shell was started from.
+const migrationsDir = "migrations"
+
 // runTablesCmd shows all tables.
 func runTablesCmd(db *genji.DB, cmd []string) error {
 	if len(cmd) > 1 {
@@ -97,6 +136,46 @@ func runIndexesCmd(db *genji.DB, in []string) error {
 	return fmt.Errorf("usage: .indexes [tablename]")
 }
 
+// runMigrateCmd runs, reverts or reports the status of the schema
+// migrations found in migrationsDir.
+func runMigrateCmd(db *genji.DB, cmd []string) error {
+	if len(cmd) < 2 {
+		return fmt.Errorf("usage: .migrate up|down [n]|status")
+	}
+
+	src := migrate.Dir(migrationsDir)
+
+	switch cmd[1] {
+	case "up":
+		return migrate.Up(db, src)
+	case "down":
+		n := 1
+		if len(cmd) > 2 {
+			if _, err := fmt.Sscanf(cmd[2], "%d", &n); err != nil {
+				return fmt.Errorf("usage: .migrate down [n]")
+			}
+		}
+		return migrate.Down(db, src, n)
+	case "status":
+		statuses, err := migrate.Status(db, src)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied at " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%s\t%s\n", s.ID, state)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("usage: .migrate up|down [n]|status")
+}
+
 // runHelpCmd shows all available commands.
 func runHelpCmd() error {
 	for _, c := range commands {
@@ -0,0 +1,60 @@
+package database
+
+// IndexConfig gained a BuildStatus field (see IndexBuildStatus) so the
+// catalog can tell a fully built index apart from one that is still
+// being backfilled asynchronously. The methods below are the catalog
+// operations index_build.go needs on top of the existing
+// AddIndexConfig/GetIndexConfig pair.
+
+// SetIndexBuildStatus updates the BuildStatus of the index catalog
+// entry identified by name.
+func (c *Catalog) SetIndexBuildStatus(name string, status IndexBuildStatus) error {
+	cfg, err := c.GetIndexConfig(name)
+	if err != nil {
+		return err
+	}
+
+	cfg.BuildStatus = status
+	return c.replaceIndexConfig(cfg)
+}
+
+// CheckpointIndexBuild persists checkpoint as the resume point for the
+// asynchronous build of the index identified by name, so that a builder
+// restarted after a crash can continue from there instead of
+// rebuilding the index from scratch.
+func (c *Catalog) CheckpointIndexBuild(name string, checkpoint []byte) error {
+	cfg, err := c.GetIndexConfig(name)
+	if err != nil {
+		return err
+	}
+
+	cfg.BuildCheckpoint = checkpoint
+	return c.replaceIndexConfig(cfg)
+}
+
+// ListBuildingIndexConfigs returns every index catalog entry whose
+// BuildStatus is still IndexBuilding, across every table. IndexBuilder
+// uses this on startup to resume backfills a previous run did not
+// finish.
+func (c *Catalog) ListBuildingIndexConfigs() ([]IndexConfig, error) {
+	tables, err := c.ListTables()
+	if err != nil {
+		return nil, err
+	}
+
+	var building []IndexConfig
+	for _, tableName := range tables {
+		cfgs, err := c.ListIndexConfigs(tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cfg := range cfgs {
+			if cfg.BuildStatus == IndexBuilding {
+				building = append(building, cfg)
+			}
+		}
+	}
+
+	return building, nil
+}
@@ -0,0 +1,60 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/engine/memengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogIndexBuildLifecycle(t *testing.T) {
+	ng := memengine.NewEngine()
+	db, err := database.New(ng)
+	require.NoError(t, err)
+
+	err = db.Update(func(tx *database.Transaction) error {
+		_, err := tx.GetTable("users")
+		require.NoError(t, err)
+
+		return tx.Catalog.AddIndexConfig(database.IndexConfig{
+			IndexName:   "users_age_idx",
+			TableName:   "users",
+			Path:        "age",
+			BuildStatus: database.IndexBuilding,
+		})
+	})
+	require.NoError(t, err)
+
+	err = db.Update(func(tx *database.Transaction) error {
+		building, err := tx.Catalog.ListBuildingIndexConfigs()
+		require.NoError(t, err)
+		require.Len(t, building, 1)
+		require.Equal(t, "users_age_idx", building[0].IndexName)
+
+		require.NoError(t, tx.Catalog.CheckpointIndexBuild("users_age_idx", []byte("somekey")))
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = db.View(func(tx *database.Transaction) error {
+		cfg, err := tx.Catalog.GetIndexConfig("users_age_idx")
+		require.NoError(t, err)
+		require.Equal(t, []byte("somekey"), cfg.BuildCheckpoint)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = db.Update(func(tx *database.Transaction) error {
+		return tx.Catalog.SetIndexBuildStatus("users_age_idx", database.IndexReady)
+	})
+	require.NoError(t, err)
+
+	err = db.Update(func(tx *database.Transaction) error {
+		building, err := tx.Catalog.ListBuildingIndexConfigs()
+		require.NoError(t, err)
+		require.Empty(t, building)
+		return nil
+	})
+	require.NoError(t, err)
+}
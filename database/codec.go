@@ -0,0 +1,57 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/document/encoding"
+)
+
+// codecMetadataKey is the engine metadata key under which the
+// identifier of the codec used to encode the database is stored.
+const codecMetadataKey = "codec"
+
+// namedCodec is implemented by codecs that report an identifier, so that
+// CheckCodec can store and verify it across reopens. Codecs that don't
+// implement it (third-party codecs predating this check) are allowed
+// through without verification.
+type namedCodec interface {
+	Name() string
+}
+
+// CheckCodec records codec's identifier in the engine metadata the first
+// time the database is created, and fails if a database created with a
+// different codec is reopened with codec. This prevents reopening a
+// database with the wrong codec from silently returning garbage
+// documents.
+func CheckCodec(ng MetadataStore, codec encoding.Codec) error {
+	nc, ok := codec.(namedCodec)
+	if !ok {
+		return nil
+	}
+
+	stored, err := ng.GetMetadata(codecMetadataKey)
+	if err != nil {
+		if err == ErrMetadataNotFound {
+			return ng.SetMetadata(codecMetadataKey, []byte(nc.Name()))
+		}
+
+		return err
+	}
+
+	if string(stored) != nc.Name() {
+		return fmt.Errorf("database was created with the %q codec, cannot open it with %q", stored, nc.Name())
+	}
+
+	return nil
+}
+
+// MetadataStore is the small subset of the engine used by CheckCodec to
+// read and write top-level metadata, independent of any table.
+type MetadataStore interface {
+	GetMetadata(key string) ([]byte, error)
+	SetMetadata(key string, value []byte) error
+}
+
+// ErrMetadataNotFound is returned by a MetadataStore when the requested
+// metadata key has not been set yet.
+var ErrMetadataNotFound = fmt.Errorf("metadata not found")
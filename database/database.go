@@ -0,0 +1,456 @@
+// Package database implements the table, index and catalog layer genji
+// builds its SQL engine on top of: Database owns the engine.Engine and
+// the catalog, Transaction is the unit of work every read and write goes
+// through, and Table/Index/FullTextIndex are what a Transaction reads
+// and writes.
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/engine"
+	"github.com/genjidb/genji/index"
+	"github.com/genjidb/genji/index/bleveindex"
+	"github.com/genjidb/genji/queue"
+)
+
+// IndexConfig describes a regular, comparison-based index as stored in
+// the catalog.
+type IndexConfig struct {
+	IndexName string
+	TableName string
+	Path      string
+
+	// BuildStatus and BuildCheckpoint track an asynchronous CREATE INDEX
+	// ... WITH (async=true): see IndexBuildStatus and
+	// IndexBuilder.build in index_build.go.
+	BuildStatus     IndexBuildStatus
+	BuildCheckpoint []byte
+}
+
+// Catalog stores the configuration of every table, index and full-text
+// index known to the database.
+type Catalog struct {
+	mu sync.Mutex
+
+	indexes         map[string]IndexConfig
+	fullTextIndexes map[string]FullTextIndexConfig
+}
+
+func newCatalog() *Catalog {
+	return &Catalog{
+		indexes:         make(map[string]IndexConfig),
+		fullTextIndexes: make(map[string]FullTextIndexConfig),
+	}
+}
+
+// AddIndexConfig registers a new regular index in the catalog.
+func (c *Catalog) AddIndexConfig(cfg IndexConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.indexes[cfg.IndexName]; ok {
+		return fmt.Errorf("index %q already exists", cfg.IndexName)
+	}
+
+	c.indexes[cfg.IndexName] = cfg
+	return nil
+}
+
+// GetIndexConfig returns the regular index catalog entry for name.
+func (c *Catalog) GetIndexConfig(name string) (IndexConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cfg, ok := c.indexes[name]
+	if !ok {
+		return IndexConfig{}, fmt.Errorf("index %q does not exist", name)
+	}
+	return cfg, nil
+}
+
+// replaceIndexConfig overwrites the catalog entry for cfg.IndexName.
+func (c *Catalog) replaceIndexConfig(cfg IndexConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.indexes[cfg.IndexName]; !ok {
+		return fmt.Errorf("index %q does not exist", cfg.IndexName)
+	}
+	c.indexes[cfg.IndexName] = cfg
+	return nil
+}
+
+// ListIndexConfigs returns every regular index declared on tableName.
+func (c *Catalog) ListIndexConfigs(tableName string) ([]IndexConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []IndexConfig
+	for _, cfg := range c.indexes {
+		if cfg.TableName == tableName {
+			out = append(out, cfg)
+		}
+	}
+	return out, nil
+}
+
+// ListTables returns the name of every table that has at least one index
+// or full-text index declared on it.
+func (c *Catalog) ListTables() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, cfg := range c.indexes {
+		if !seen[cfg.TableName] {
+			seen[cfg.TableName] = true
+			out = append(out, cfg.TableName)
+		}
+	}
+	for _, cfg := range c.fullTextIndexes {
+		if !seen[cfg.TableName] {
+			seen[cfg.TableName] = true
+			out = append(out, cfg.TableName)
+		}
+	}
+	return out, nil
+}
+
+// AddFullTextIndexConfig registers a new full-text index in the catalog.
+func (c *Catalog) AddFullTextIndexConfig(cfg FullTextIndexConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.fullTextIndexes[cfg.IndexName]; ok {
+		return fmt.Errorf("index %q already exists", cfg.IndexName)
+	}
+	c.fullTextIndexes[cfg.IndexName] = cfg
+	return nil
+}
+
+// GetFullTextIndexConfig returns the full-text index catalog entry for
+// name.
+func (c *Catalog) GetFullTextIndexConfig(name string) (FullTextIndexConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cfg, ok := c.fullTextIndexes[name]
+	if !ok {
+		return FullTextIndexConfig{}, fmt.Errorf("fulltext index %q does not exist", name)
+	}
+	return cfg, nil
+}
+
+// ListFullTextIndexConfigs returns every full-text index declared on
+// tableName.
+func (c *Catalog) ListFullTextIndexConfigs(tableName string) ([]FullTextIndexConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []FullTextIndexConfig
+	for _, cfg := range c.fullTextIndexes {
+		if cfg.TableName == tableName {
+			out = append(out, cfg)
+		}
+	}
+	return out, nil
+}
+
+// Database is a collection of tables, backed by an engine.Engine.
+type Database struct {
+	ng engine.Engine
+
+	mu      sync.Mutex
+	catalog *Catalog
+	tables  map[string]*Table
+	indexes map[string]*memIndex
+
+	fullTextIndexes fullTextIndexSet
+
+	// indexBuildQueue is where an asynchronous CREATE INDEX enqueues its
+	// indexBuildJob. It is nil until SetIndexBuildQueue is called, which
+	// genji.New does right after creating the queue the IndexBuilder
+	// drains, so the two always agree on which queue is in play.
+	indexBuildQueue queue.Queue
+}
+
+// SetIndexBuildQueue wires q as the destination for every asynchronous
+// CREATE INDEX started against db from this point on.
+func (db *Database) SetIndexBuildQueue(q queue.Queue) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.indexBuildQueue = q
+}
+
+// New creates a Database on top of ng.
+func New(ng engine.Engine) (*Database, error) {
+	return &Database{
+		ng:      ng,
+		catalog: newCatalog(),
+		tables:  make(map[string]*Table),
+		indexes: make(map[string]*memIndex),
+		// Full-text indexes live on disk as bleve indexes rather than in
+		// ng, since engine.Engine has no notion of a full-text index.
+		// "." is a reasonable default for a database rooted in the
+		// current directory; callers that need the bleve indexes to live
+		// alongside engine-specific storage can override it before the
+		// first full-text index is created.
+		fullTextIndexes: fullTextIndexSet{Path: "."},
+	}, nil
+}
+
+func (db *Database) indexFor(name string) *memIndex {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	idx, ok := db.indexes[name]
+	if !ok {
+		idx = &memIndex{}
+		db.indexes[name] = idx
+	}
+	return idx
+}
+
+// Transaction is the unit of work every table, index and full-text index
+// read or write goes through.
+type Transaction struct {
+	DB      *Database
+	Catalog *Catalog
+
+	writable bool
+
+	fullTextBatches []*bleveindex.Index
+}
+
+// View runs fn in a read-only transaction.
+func (db *Database) View(fn func(tx *Transaction) error) error {
+	return fn(&Transaction{DB: db, Catalog: db.catalog})
+}
+
+// Update runs fn in a writable transaction, flushing every buffered
+// full-text index batch if fn succeeds and discarding them otherwise, so
+// that full-text index writes commit and roll back atomically with the
+// rest of the transaction.
+func (db *Database) Update(fn func(tx *Transaction) error) error {
+	tx := &Transaction{DB: db, Catalog: db.catalog, writable: true}
+
+	if err := fn(tx); err != nil {
+		tx.fullTextOnRollback()
+		return err
+	}
+
+	return tx.fullTextOnCommit()
+}
+
+// GetTable returns the table with the given name, creating it on first
+// use.
+func (tx *Transaction) GetTable(name string) (*Table, error) {
+	tx.DB.mu.Lock()
+	defer tx.DB.mu.Unlock()
+
+	tb, ok := tx.DB.tables[name]
+	if !ok {
+		tb = &Table{name: name}
+		tx.DB.tables[name] = tb
+	}
+	tb.tx = tx
+
+	return tb, nil
+}
+
+// GetIndex returns the regular index with the given name.
+func (tx *Transaction) GetIndex(name string) (index.Index, error) {
+	cfg, err := tx.Catalog.GetIndexConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.DB.indexFor(cfg.IndexName), nil
+}
+
+// keyedDocument wraps a document.Document with the key it was read from,
+// so that Table.GetDocumentKey can recover it after Iterate/IterateFrom.
+type keyedDocument struct {
+	document.Document
+	key []byte
+}
+
+// Table represents a collection of documents, as read and written
+// through a Transaction.
+type Table struct {
+	tx   *Transaction
+	name string
+
+	mu   sync.Mutex
+	rows []tableRow
+}
+
+type tableRow struct {
+	key []byte
+	doc document.Document
+}
+
+// Insert adds d to the table, assigning it a new key, and propagates the
+// change to every full-text index and every index under construction on
+// the table.
+func (t *Table) Insert(d document.Document) ([]byte, error) {
+	t.mu.Lock()
+	key := []byte(fmt.Sprintf("%08d", len(t.rows)))
+	t.rows = append(t.rows, tableRow{key: key, doc: d})
+	t.mu.Unlock()
+
+	if err := t.tx.onFullTextDocumentChange(t.name, key, nil, d); err != nil {
+		return nil, err
+	}
+	if err := t.tx.onIndexDocumentChange(t.name, key, nil, d); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Replace replaces the document stored at key with d.
+func (t *Table) Replace(key []byte, d document.Document) error {
+	old, err := t.replace(key, d)
+	if err != nil {
+		return err
+	}
+
+	if err := t.tx.onFullTextDocumentChange(t.name, key, old, d); err != nil {
+		return err
+	}
+	return t.tx.onIndexDocumentChange(t.name, key, old, d)
+}
+
+func (t *Table) replace(key []byte, d document.Document) (document.Document, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, r := range t.rows {
+		if bytes.Equal(r.key, key) {
+			old := r.doc
+			t.rows[i].doc = d
+			return old, nil
+		}
+	}
+
+	return nil, ErrDocumentNotFound
+}
+
+// Delete removes the document stored at key.
+func (t *Table) Delete(key []byte) error {
+	old, err := t.delete(key)
+	if err != nil {
+		return err
+	}
+
+	if err := t.tx.onFullTextDocumentChange(t.name, key, old, nil); err != nil {
+		return err
+	}
+	return t.tx.onIndexDocumentChange(t.name, key, old, nil)
+}
+
+func (t *Table) delete(key []byte) (document.Document, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, r := range t.rows {
+		if bytes.Equal(r.key, key) {
+			old := r.doc
+			t.rows = append(t.rows[:i], t.rows[i+1:]...)
+			return old, nil
+		}
+	}
+
+	return nil, ErrDocumentNotFound
+}
+
+// ErrDocumentNotFound is returned when a table lookup finds no document
+// for the given key.
+var ErrDocumentNotFound = fmt.Errorf("document not found")
+
+// GetDocument returns the document stored at key.
+func (t *Table) GetDocument(key []byte) (document.Document, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, r := range t.rows {
+		if bytes.Equal(r.key, key) {
+			return r.doc, nil
+		}
+	}
+	return nil, ErrDocumentNotFound
+}
+
+// GetDocumentKey returns the key d was read from. d must have been
+// obtained from this table's Iterate, IterateFrom or GetDocument.
+func (t *Table) GetDocumentKey(d document.Document) ([]byte, error) {
+	if kd, ok := d.(keyedDocument); ok {
+		return kd.key, nil
+	}
+	return nil, fmt.Errorf("document was not read from this table")
+}
+
+func (t *Table) sortedRows() []tableRow {
+	t.mu.Lock()
+	rows := make([]tableRow, len(t.rows))
+	copy(rows, t.rows)
+	t.mu.Unlock()
+
+	sort.Slice(rows, func(i, j int) bool { return bytes.Compare(rows[i].key, rows[j].key) < 0 })
+	return rows
+}
+
+// Iterate calls fn for every document of the table, in key order.
+func (t *Table) Iterate(fn func(d document.Document) error) error {
+	return t.IterateFrom(nil, fn)
+}
+
+// IterateFrom calls fn for every document of the table whose key comes
+// strictly after resumeKey (or every document, if resumeKey is nil), in
+// key order. IndexBuilder uses this to resume a backfill from its last
+// checkpoint.
+func (t *Table) IterateFrom(resumeKey []byte, fn func(d document.Document) error) error {
+	started := resumeKey == nil
+
+	for _, r := range t.sortedRows() {
+		if !started {
+			if bytes.Compare(r.key, resumeKey) <= 0 {
+				continue
+			}
+			started = true
+		}
+
+		if err := fn(keyedDocument{Document: r.doc, key: r.key}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TableIndex describes one of the indexes declared on a Table.
+type TableIndex struct {
+	Opts IndexConfig
+}
+
+// Indexes returns every regular index declared on the table.
+func (t *Table) Indexes() ([]TableIndex, error) {
+	cfgs, err := t.tx.Catalog.ListIndexConfigs(t.name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TableIndex, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		out = append(out, TableIndex{Opts: cfg})
+	}
+	return out, nil
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/index"
+	"github.com/genjidb/genji/index/bleveindex"
+)
+
+// FullTextIndexConfig holds the configuration of a full-text index, as
+// stored in the catalog alongside regular IndexConfig entries.
+type FullTextIndexConfig struct {
+	IndexName string
+	TableName string
+	Path      string
+}
+
+// fullTextIndexForWrite returns the *bleveindex.Index backing cfg and
+// makes sure it has a batch open for the lifetime of tx. Every write
+// path (the initial backfill, ordinary document changes and explicit
+// reads through GetFullTextIndex) must go through this helper rather
+// than tx.DB.fullTextIndexes.Get directly, otherwise writes bypass the
+// batch and land in the live bleve index immediately, instead of being
+// discarded on rollback like the rest of the transaction. Calling
+// StartBatch more than once per transaction would also reset the batch
+// and drop whatever was buffered so far, so this only starts one the
+// first time the index is touched in tx.
+func (tx *Transaction) fullTextIndexForWrite(cfg FullTextIndexConfig) (*bleveindex.Index, error) {
+	idx, err := tx.DB.fullTextIndexes.Get(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, started := range tx.fullTextBatches {
+		if started == idx {
+			return idx, nil
+		}
+	}
+
+	idx.StartBatch()
+	tx.fullTextBatches = append(tx.fullTextBatches, idx)
+
+	return idx, nil
+}
+
+// GetFullTextIndex returns the full-text index with the given name. It
+// behaves like GetIndex but for indexes created with FULLTEXT INDEX.
+func (tx *Transaction) GetFullTextIndex(name string) (index.FullTextIndex, error) {
+	info, err := tx.Catalog.GetFullTextIndexConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.fullTextIndexForWrite(info)
+}
+
+// CreateFullTextIndex creates a new full-text index on cfg.TableName at
+// cfg.Path and backfills it with the existing contents of the table.
+func (tx *Transaction) CreateFullTextIndex(cfg FullTextIndexConfig) error {
+	if err := tx.Catalog.AddFullTextIndexConfig(cfg); err != nil {
+		return err
+	}
+
+	idx, err := tx.fullTextIndexForWrite(cfg)
+	if err != nil {
+		return err
+	}
+
+	tb, err := tx.GetTable(cfg.TableName)
+	if err != nil {
+		return err
+	}
+
+	return tb.Iterate(func(d document.Document) error {
+		v, err := d.GetByField(cfg.Path)
+		if err != nil {
+			// A row missing cfg.Path is skipped rather than failing the
+			// whole backfill, the same way onFullTextDocumentChange
+			// treats a live write to a row missing the path: it simply
+			// isn't indexed, instead of blocking CREATE FULLTEXT INDEX
+			// on every row in the table having the field.
+			return nil
+		}
+
+		key, err := tb.GetDocumentKey(d)
+		if err != nil {
+			return err
+		}
+
+		return idx.Set(key, v)
+	})
+}
+
+// onDocumentChange is called by the table implementation whenever a
+// document is inserted, replaced or deleted, so that every full-text
+// index covering the table stays in sync with the rest of the
+// transaction. Like every other catalog mutation, the change only
+// becomes durable when the transaction commits, and is discarded on
+// rollback by OnRollback.
+func (tx *Transaction) onFullTextDocumentChange(tableName string, key []byte, old, new document.Document) error {
+	configs, err := tx.Catalog.ListFullTextIndexConfigs(tableName)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		idx, err := tx.fullTextIndexForWrite(cfg)
+		if err != nil {
+			return err
+		}
+
+		if old != nil {
+			if err := idx.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		if new != nil {
+			v, err := new.GetByField(cfg.Path)
+			if err == nil {
+				if err := idx.Set(key, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// onCommit flushes every bleve batch opened during the transaction so
+// the full-text indexes become visible at the same time as the rest of
+// the engine commit.
+func (tx *Transaction) fullTextOnCommit() error {
+	for _, idx := range tx.fullTextBatches {
+		if err := idx.FlushBatch(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// onRollback discards every bleve batch opened during the transaction.
+func (tx *Transaction) fullTextOnRollback() {
+	for _, idx := range tx.fullTextBatches {
+		idx.DiscardBatch()
+	}
+}
+
+// fullTextIndexSet caches the *bleveindex.Index instances so that every
+// transaction against the same full-text index reuses the same
+// underlying bleve index rather than reopening it.
+type fullTextIndexSet struct {
+	indexes map[string]*bleveindex.Index
+	// Path is the directory where the on-disk bleve indexes live. It
+	// is derived from the engine's own storage directory so the two
+	// stay consistent across reopens.
+	Path string
+}
+
+// Get returns the bleve index for cfg, opening it the first time it is
+// requested.
+func (s *fullTextIndexSet) Get(cfg FullTextIndexConfig) (*bleveindex.Index, error) {
+	if idx, ok := s.indexes[cfg.IndexName]; ok {
+		return idx, nil
+	}
+
+	idx, err := bleveindex.New(s.Path + "/" + cfg.IndexName + ".bleve")
+	if err != nil {
+		return nil, err
+	}
+
+	if s.indexes == nil {
+		s.indexes = make(map[string]*bleveindex.Index)
+	}
+	s.indexes[cfg.IndexName] = idx
+
+	return idx, nil
+}
@@ -0,0 +1,96 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/engine/memengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullTextIndexCommitRollback(t *testing.T) {
+	newArticle := func(body string) *document.FieldBuffer {
+		var fb document.FieldBuffer
+		fb.Add("body", document.NewTextValue(body))
+		return &fb
+	}
+
+	cfg := database.FullTextIndexConfig{
+		IndexName: "articles_body_idx",
+		TableName: "articles",
+		Path:      "body",
+	}
+
+	t.Run("backfill becomes searchable only once the transaction commits", func(t *testing.T) {
+		ng := memengine.NewEngine()
+		db, err := database.New(ng)
+		require.NoError(t, err)
+
+		err = db.Update(func(tx *database.Transaction) error {
+			tb, err := tx.GetTable("articles")
+			require.NoError(t, err)
+			_, err = tb.Insert(newArticle("genji is a database"))
+			require.NoError(t, err)
+
+			return tx.CreateFullTextIndex(cfg)
+		})
+		require.NoError(t, err)
+
+		err = db.View(func(tx *database.Transaction) error {
+			idx, err := tx.GetFullTextIndex(cfg.IndexName)
+			require.NoError(t, err)
+
+			var hits int
+			err = idx.Search("genji", func(key []byte, score float64) error {
+				hits++
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, hits)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("a rolled back insert never becomes searchable", func(t *testing.T) {
+		ng := memengine.NewEngine()
+		db, err := database.New(ng)
+		require.NoError(t, err)
+
+		err = db.Update(func(tx *database.Transaction) error {
+			return tx.CreateFullTextIndex(cfg)
+		})
+		require.NoError(t, err)
+
+		sentinel := &sentinelErr{}
+		err = db.Update(func(tx *database.Transaction) error {
+			tb, err := tx.GetTable("articles")
+			require.NoError(t, err)
+			_, err = tb.Insert(newArticle("genji is a database"))
+			require.NoError(t, err)
+
+			return sentinel
+		})
+		require.Equal(t, sentinel, err)
+
+		err = db.View(func(tx *database.Transaction) error {
+			idx, err := tx.GetFullTextIndex(cfg.IndexName)
+			require.NoError(t, err)
+
+			var hits int
+			err = idx.Search("genji", func(key []byte, score float64) error {
+				hits++
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, 0, hits)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+type sentinelErr struct{}
+
+func (*sentinelErr) Error() string { return "sentinel" }
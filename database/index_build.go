@@ -0,0 +1,300 @@
+package database
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/queue"
+)
+
+// IndexBuildStatus records the lifecycle of an index created with
+// CREATE INDEX ... WITH (async=true). A building index is not yet safe
+// to plan queries against: tree.newIndexInputNode must refuse to use it
+// and the optimizer falls back to a table scan until it flips to ready.
+type IndexBuildStatus string
+
+const (
+	// IndexBuilding means the backfill has not completed yet.
+	IndexBuilding IndexBuildStatus = "building"
+	// IndexReady means the index reflects the full contents of the
+	// table and can be planned against.
+	IndexReady IndexBuildStatus = "ready"
+)
+
+// indexBuildJob is the payload pushed to the build queue for every
+// asynchronous CREATE INDEX. It is replayed by the builder on restart,
+// using Checkpoint to avoid redoing work that was already checkpointed
+// before a crash.
+type indexBuildJob struct {
+	IndexName  string
+	TableName  string
+	Path       string
+	Checkpoint []byte
+}
+
+// CreateIndex creates a new index. If async is true, the index is
+// marked IndexBuilding and the call returns immediately after enqueuing
+// an indexBuildJob on tx.DB.indexBuildQueue; the IndexBuilder goroutine
+// started by genji.New performs the backfill and flips the index to
+// IndexReady once it catches up with the side-log.
+func (tx *Transaction) CreateIndex(cfg IndexConfig, async bool) error {
+	cfg.BuildStatus = IndexBuilding
+	if !async {
+		cfg.BuildStatus = IndexReady
+	}
+
+	if err := tx.Catalog.AddIndexConfig(cfg); err != nil {
+		return err
+	}
+
+	if !async {
+		return tx.buildIndexNow(cfg)
+	}
+
+	job := indexBuildJob{
+		IndexName: cfg.IndexName,
+		TableName: cfg.TableName,
+		Path:      cfg.Path,
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return tx.DB.indexBuildQueue.Push(data)
+}
+
+// buildIndexNow backfills cfg synchronously, used for the non-async
+// path so CREATE INDEX keeps its previous blocking behavior.
+func (tx *Transaction) buildIndexNow(cfg IndexConfig) error {
+	idx, err := tx.GetIndex(cfg.IndexName)
+	if err != nil {
+		return err
+	}
+
+	tb, err := tx.GetTable(cfg.TableName)
+	if err != nil {
+		return err
+	}
+
+	err = tb.Iterate(func(d document.Document) error {
+		v, err := d.GetByField(cfg.Path)
+		if err != nil {
+			return nil
+		}
+
+		key, err := tb.GetDocumentKey(d)
+		if err != nil {
+			return err
+		}
+
+		return idx.Set(v, key)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Catalog.SetIndexBuildStatus(cfg.IndexName, IndexReady)
+}
+
+// IndexBuilder drains the persistent build queue and backfills indexes
+// created with WITH (async=true). One builder is started by genji.New
+// and stopped by DB.Close.
+type IndexBuilder struct {
+	db    *Database
+	queue queue.Queue
+
+	wg   sync.WaitGroup
+	stop chan struct{}
+}
+
+// NewIndexBuilder creates a builder that drains q on behalf of db.
+func NewIndexBuilder(db *Database, q queue.Queue) *IndexBuilder {
+	return &IndexBuilder{
+		db:    db,
+		queue: q,
+		stop:  make(chan struct{}),
+	}
+}
+
+// Start runs the builder loop in a background goroutine. Before doing
+// so, it re-enqueues every index the catalog still has marked
+// IndexBuilding from its last checkpoint. That covers a build
+// interrupted by a crash, including one that happened between the
+// queue handing out a job and the next checkpoint being recorded for
+// it, since in that case the job itself is gone but the catalog entry
+// is still IndexBuilding.
+func (b *IndexBuilder) Start() error {
+	if err := b.resumeOrphaned(); err != nil {
+		return err
+	}
+
+	b.wg.Add(1)
+	go b.run()
+	return nil
+}
+
+// resumeOrphaned re-pushes an indexBuildJob for every index the catalog
+// reports as still IndexBuilding, so IndexBuilder.run picks it back up.
+func (b *IndexBuilder) resumeOrphaned() error {
+	return b.db.View(func(tx *Transaction) error {
+		configs, err := tx.Catalog.ListBuildingIndexConfigs()
+		if err != nil {
+			return err
+		}
+
+		for _, cfg := range configs {
+			job := indexBuildJob{
+				IndexName:  cfg.IndexName,
+				TableName:  cfg.TableName,
+				Path:       cfg.Path,
+				Checkpoint: cfg.BuildCheckpoint,
+			}
+
+			data, err := json.Marshal(job)
+			if err != nil {
+				return err
+			}
+
+			if err := b.queue.Push(data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Stop signals the builder loop to exit and waits for it to return.
+func (b *IndexBuilder) Stop() {
+	close(b.stop)
+	b.queue.Close()
+	b.wg.Wait()
+}
+
+func (b *IndexBuilder) run() {
+	defer b.wg.Done()
+
+	for {
+		data, err := b.queue.Pop()
+		if err != nil {
+			return
+		}
+
+		var job indexBuildJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			continue
+		}
+
+		b.build(job)
+	}
+}
+
+// pendingRow is a (key, value) pair collected from a read-only pass over
+// the table, waiting to be applied to the index in its own write
+// transaction.
+type pendingRow struct {
+	key   []byte
+	value document.Value
+}
+
+// build backfills job.IndexName in batches, checkpointing the last key
+// processed after each batch so a crash mid-build resumes from there
+// instead of redoing the whole index.
+//
+// Collecting a batch and applying it are deliberately two separate
+// steps: collection runs inside a single read-only transaction that is
+// allowed to stop early (returning errBatchFull, which only aborts that
+// harmless read), while each row is then indexed in its own committed
+// write transaction. That way a crash partway through a batch only
+// loses the not-yet-committed rows, instead of rolling back every
+// idx.Set already made in the batch the way a shared write transaction
+// would.
+func (b *IndexBuilder) build(job indexBuildJob) {
+	const batchSize = 1000
+
+	resumeKey := job.Checkpoint
+
+	for {
+		var batch []pendingRow
+
+		err := b.db.View(func(tx *Transaction) error {
+			tb, err := tx.GetTable(job.TableName)
+			if err != nil {
+				return err
+			}
+
+			return tb.IterateFrom(resumeKey, func(d document.Document) error {
+				if len(batch) >= batchSize {
+					return errBatchFull
+				}
+
+				v, err := d.GetByField(job.Path)
+				if err != nil {
+					return nil
+				}
+
+				key, err := tb.GetDocumentKey(d)
+				if err != nil {
+					return err
+				}
+
+				batch = append(batch, pendingRow{key: key, value: v})
+				return nil
+			})
+		})
+		if err != nil && err != errBatchFull {
+			return
+		}
+
+		if len(batch) == 0 {
+			// The main scan is done, but rows the scan already passed
+			// may have been written to in the meantime. Keep draining
+			// the side-log until a pass finds nothing left before
+			// flipping the index to ready.
+			for {
+				drained, err := b.drainSideLog(job)
+				if err != nil {
+					return
+				}
+				if drained == 0 {
+					break
+				}
+			}
+
+			b.db.Update(func(tx *Transaction) error {
+				return tx.Catalog.SetIndexBuildStatus(job.IndexName, IndexReady)
+			})
+			return
+		}
+
+		for _, row := range batch {
+			err := b.db.Update(func(tx *Transaction) error {
+				idx, err := tx.GetIndex(job.IndexName)
+				if err != nil {
+					return err
+				}
+
+				return idx.Set(row.value, row.key)
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		resumeKey = batch[len(batch)-1].key
+		job.Checkpoint = resumeKey
+
+		b.db.Update(func(tx *Transaction) error {
+			return tx.Catalog.CheckpointIndexBuild(job.IndexName, resumeKey)
+		})
+	}
+}
+
+var errBatchFull = indexBuildBatchFull{}
+
+type indexBuildBatchFull struct{}
+
+func (indexBuildBatchFull) Error() string { return "index build batch full" }
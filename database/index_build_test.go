@@ -0,0 +1,104 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/engine/memengine"
+	"github.com/genjidb/genji/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserDoc(age int64) *document.FieldBuffer {
+	var fb document.FieldBuffer
+	fb.Add("age", document.NewIntegerValue(age))
+	return &fb
+}
+
+// waitForIndexReady polls, rather than sleeping a fixed duration, since
+// the builder runs its batches on its own goroutine with no signal this
+// test can otherwise wait on.
+func waitForIndexReady(t *testing.T, db *database.Database, indexName string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var ready bool
+		err := db.View(func(tx *database.Transaction) error {
+			cfg, err := tx.Catalog.GetIndexConfig(indexName)
+			if err != nil {
+				return err
+			}
+			ready = cfg.BuildStatus == database.IndexReady
+			return nil
+		})
+		require.NoError(t, err)
+
+		if ready {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("index %q never became ready", indexName)
+}
+
+func TestAsyncIndexBuildBackfillsAndBecomesReady(t *testing.T) {
+	ng := memengine.NewEngine()
+	db, err := database.New(ng)
+	require.NoError(t, err)
+
+	q := queue.NewMemQueue(10)
+	db.SetIndexBuildQueue(q)
+
+	builder := database.NewIndexBuilder(db, q)
+	require.NoError(t, builder.Start())
+	defer builder.Stop()
+
+	err = db.Update(func(tx *database.Transaction) error {
+		tb, err := tx.GetTable("users")
+		require.NoError(t, err)
+
+		for _, age := range []int64{10, 20, 30} {
+			if _, err := tb.Insert(newUserDoc(age)); err != nil {
+				return err
+			}
+		}
+
+		return tx.CreateIndex(database.IndexConfig{
+			IndexName: "users_age_idx",
+			TableName: "users",
+			Path:      "age",
+		}, true)
+	})
+	require.NoError(t, err)
+
+	// A write racing the backfill must still end up indexed, via the
+	// side-log rather than the main scan.
+	err = db.Update(func(tx *database.Transaction) error {
+		tb, err := tx.GetTable("users")
+		require.NoError(t, err)
+		_, err = tb.Insert(newUserDoc(40))
+		return err
+	})
+	require.NoError(t, err)
+
+	waitForIndexReady(t, db, "users_age_idx")
+
+	err = db.View(func(tx *database.Transaction) error {
+		idx, err := tx.GetIndex("users_age_idx")
+		require.NoError(t, err)
+
+		var ages []int64
+		err = idx.AscendGreaterOrEqual(document.Value{}, func(val document.Value, key []byte) error {
+			ages = append(ages, val.V.(int64))
+			return nil
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []int64{10, 20, 30, 40}, ages)
+		return nil
+	})
+	require.NoError(t, err)
+}
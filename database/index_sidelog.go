@@ -0,0 +1,212 @@
+package database
+
+import (
+	"encoding/binary"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/engine"
+)
+
+// sideLogStoreName returns the engine store used to log the keys of
+// rows written to tableName while indexName is still IndexBuilding, so
+// IndexBuilder.drainSideLog can catch up on writes the backfill scan
+// already passed before flipping the index to IndexReady.
+func sideLogStoreName(indexName string) []byte {
+	return []byte("__genji_index_sidelog_" + indexName)
+}
+
+// onIndexDocumentChange is called by the table implementation whenever a
+// document is inserted, replaced or deleted, so that every regular index
+// on tableName stays in sync with the rest of the transaction: a ready
+// index is updated in place with idx.Set/idx.Delete, the same way
+// onFullTextDocumentChange updates a full-text index. An index still
+// being built asynchronously cannot be updated in place mid-scan
+// instead: a write to a key the backfill already passed would be
+// silently lost, so it gets a side-log entry instead, replayed by
+// drainSideLog once the main scan is done.
+func (tx *Transaction) onIndexDocumentChange(tableName string, key []byte, old, new document.Document) error {
+	configs, err := tx.Catalog.ListIndexConfigs(tableName)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if cfg.BuildStatus == IndexBuilding {
+			if err := appendSideLog(tx.DB.ng, cfg.IndexName, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		idx, err := tx.GetIndex(cfg.IndexName)
+		if err != nil {
+			return err
+		}
+
+		if old != nil {
+			// index.Index.Delete keys deletion off of key, not v: every
+			// index.Index implementation in this tree (memIndex today)
+			// looks the entry up by key, so the zero Value here is fine
+			// even when old never had cfg.Path in the first place.
+			if err := idx.Delete(document.Value{}, key); err != nil {
+				return err
+			}
+		}
+
+		if new != nil {
+			v, err := new.GetByField(cfg.Path)
+			if err == nil {
+				if err := idx.Set(v, key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendSideLog records key under indexName's side-log store, in its
+// own short transaction against the engine. It runs independently of
+// tx's own engine transaction: this is a simplification (the append
+// isn't atomic with the row write it logs) that is safe here because
+// replay is idempotent — re-applying the same key to the index twice,
+// or applying it slightly out of order with a later write to the same
+// key, converges to the same result once the side-log is drained.
+func appendSideLog(ng engine.Engine, indexName string, key []byte) error {
+	etx, err := ng.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer etx.Rollback()
+
+	storeName := sideLogStoreName(indexName)
+
+	st, err := etx.GetStore(storeName)
+	if err != nil {
+		if err := etx.CreateStore(storeName); err != nil {
+			return err
+		}
+		st, err = etx.GetStore(storeName)
+		if err != nil {
+			return err
+		}
+	}
+
+	seq, err := nextSideLogSeq(st)
+	if err != nil {
+		return err
+	}
+
+	if err := st.Put(seq, key); err != nil {
+		return err
+	}
+
+	return etx.Commit()
+}
+
+func nextSideLogSeq(st engine.Store) ([]byte, error) {
+	it := st.NewIterator(engine.IteratorOptions{Reverse: true})
+	defer it.Close()
+
+	it.Seek(nil)
+
+	var n uint64
+	if it.Valid() {
+		n = binary.BigEndian.Uint64(it.Item().Key()) + 1
+	}
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, n)
+	return buf, nil
+}
+
+// drainSideLog replays every key recorded against indexName since the
+// backfill began, applying idx.Set (if the row still exists) or
+// idx.Delete (if it was removed in the meantime). It is called by
+// IndexBuilder.build once its main table scan is exhausted, and again
+// after that if more entries arrived while draining, until the side-log
+// comes up empty — at which point it is safe to flip the index to
+// IndexReady.
+func (b *IndexBuilder) drainSideLog(job indexBuildJob) (drained int, err error) {
+	storeName := sideLogStoreName(job.IndexName)
+
+	for {
+		var key []byte
+
+		err := b.db.Update(func(tx *Transaction) error {
+			etx, err := tx.DB.ng.Begin(true)
+			if err != nil {
+				return err
+			}
+			defer etx.Rollback()
+
+			st, err := etx.GetStore(storeName)
+			if err != nil {
+				// no side-log entries were ever recorded for this index.
+				return errSideLogEmpty
+			}
+
+			it := st.NewIterator(engine.IteratorOptions{})
+			defer it.Close()
+
+			it.Seek(nil)
+			if !it.Valid() {
+				return errSideLogEmpty
+			}
+
+			seqKey := it.Item().Key()
+			key, err = it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			if err := st.Delete(seqKey); err != nil {
+				return err
+			}
+
+			return etx.Commit()
+		})
+		if err == errSideLogEmpty {
+			return drained, nil
+		}
+		if err != nil {
+			return drained, err
+		}
+
+		err = b.db.Update(func(tx *Transaction) error {
+			idx, err := tx.GetIndex(job.IndexName)
+			if err != nil {
+				return err
+			}
+
+			tb, err := tx.GetTable(job.TableName)
+			if err != nil {
+				return err
+			}
+
+			d, err := tb.GetDocument(key)
+			if err != nil {
+				return idx.Delete(document.Value{}, key)
+			}
+
+			v, err := d.GetByField(job.Path)
+			if err != nil {
+				return idx.Delete(document.Value{}, key)
+			}
+
+			return idx.Set(v, key)
+		})
+		if err != nil {
+			return drained, err
+		}
+
+		drained++
+	}
+}
+
+var errSideLogEmpty = sideLogEmpty{}
+
+type sideLogEmpty struct{}
+
+func (sideLogEmpty) Error() string { return "side log empty" }
@@ -0,0 +1,63 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/engine/memengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexStaysUpToDateOnceReady(t *testing.T) {
+	newUser := func(age int64) *document.FieldBuffer {
+		var fb document.FieldBuffer
+		fb.Add("age", document.NewIntegerValue(age))
+		return &fb
+	}
+
+	ng := memengine.NewEngine()
+	db, err := database.New(ng)
+	require.NoError(t, err)
+
+	err = db.Update(func(tx *database.Transaction) error {
+		tb, err := tx.GetTable("users")
+		require.NoError(t, err)
+		_, err = tb.Insert(newUser(30))
+		require.NoError(t, err)
+
+		return tx.CreateIndex(database.IndexConfig{
+			IndexName: "users_age_idx",
+			TableName: "users",
+			Path:      "age",
+		}, false)
+	})
+	require.NoError(t, err)
+
+	// A row written after the index became IndexReady must still show up
+	// in it: onIndexDocumentChange used to only maintain an index that
+	// was still IndexBuilding, leaving every ready index frozen at
+	// whatever the backfill saw.
+	err = db.Update(func(tx *database.Transaction) error {
+		tb, err := tx.GetTable("users")
+		require.NoError(t, err)
+		_, err = tb.Insert(newUser(40))
+		return err
+	})
+	require.NoError(t, err)
+
+	err = db.View(func(tx *database.Transaction) error {
+		idx, err := tx.GetIndex("users_age_idx")
+		require.NoError(t, err)
+
+		var ages []int64
+		err = idx.AscendGreaterOrEqual(document.Value{}, func(val document.Value, key []byte) error {
+			ages = append(ages, val.V.(int64))
+			return nil
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []int64{30, 40}, ages)
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/index"
+)
+
+// memIndex is an in-memory index.Index, good enough to back regular
+// CREATE INDEX indexes until a real on-disk, engine-backed
+// implementation is plugged in.
+type memIndex struct {
+	mu      sync.Mutex
+	entries []indexEntry
+}
+
+type indexEntry struct {
+	val document.Value
+	key []byte
+}
+
+var _ index.Index = (*memIndex)(nil)
+
+// Set implements the index.Index interface.
+func (m *memIndex) Set(v document.Value, key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, indexEntry{val: v, key: key})
+	return nil
+}
+
+// Delete implements the index.Index interface.
+func (m *memIndex) Delete(v document.Value, key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, e := range m.entries {
+		if bytes.Equal(e.key, key) {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memIndex) sortedEntries(reverse bool) []indexEntry {
+	m.mu.Lock()
+	entries := make([]indexEntry, len(m.entries))
+	copy(entries, m.entries)
+	m.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool { return compareValues(entries[i].val, entries[j].val) < 0 })
+	if reverse {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+	return entries
+}
+
+// AscendGreaterOrEqual implements the index.Index interface.
+func (m *memIndex) AscendGreaterOrEqual(pivot document.Value, fn func(val document.Value, key []byte) error) error {
+	hasPivot := pivot.Type != document.NullValue || pivot.V != nil
+
+	for _, e := range m.sortedEntries(false) {
+		if hasPivot && compareValues(e.val, pivot) < 0 {
+			continue
+		}
+		if err := fn(e.val, e.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DescendLessOrEqual implements the index.Index interface.
+func (m *memIndex) DescendLessOrEqual(pivot document.Value, fn func(val document.Value, key []byte) error) error {
+	hasPivot := pivot.Type != document.NullValue || pivot.V != nil
+
+	for _, e := range m.sortedEntries(true) {
+		if hasPivot && compareValues(e.val, pivot) > 0 {
+			continue
+		}
+		if err := fn(e.val, e.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Truncate implements the index.Index interface.
+func (m *memIndex) Truncate() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = nil
+	return nil
+}
+
+// compareValues orders two values of the same dynamic type. Values of
+// different types sort by type name, which is arbitrary but consistent.
+func compareValues(a, b document.Value) int {
+	switch av := a.V.(type) {
+	case int64:
+		if bv, ok := b.V.(int64); ok {
+			switch {
+			case av < bv:
+				return -1
+			case av > bv:
+				return 1
+			default:
+				return 0
+			}
+		}
+	case float64:
+		if bv, ok := b.V.(float64); ok {
+			switch {
+			case av < bv:
+				return -1
+			case av > bv:
+				return 1
+			default:
+				return 0
+			}
+		}
+	case string:
+		if bv, ok := b.V.(string); ok {
+			return bytes.Compare([]byte(av), []byte(bv))
+		}
+	}
+
+	return int(a.Type) - int(b.Type)
+}
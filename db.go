@@ -0,0 +1,75 @@
+package genji
+
+import (
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/document/encoding"
+	"github.com/genjidb/genji/document/encoding/msgpack"
+	"github.com/genjidb/genji/engine"
+	"github.com/genjidb/genji/queue"
+	"github.com/genjidb/genji/queue/enginequeue"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DB represents a collection of tables persisted on top of an engine.
+type DB struct {
+	ng    engine.Engine
+	db    *database.Database
+	codec encoding.Codec
+
+	tracerProvider trace.TracerProvider
+
+	indexBuildQueue queue.Queue
+	indexBuilder    *database.IndexBuilder
+}
+
+// New initializes the DB using the given engine, applying opts in
+// order.
+func New(ng engine.Engine, opts ...Option) (*DB, error) {
+	ddb, err := database.New(ng)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{
+		ng:    ng,
+		db:    ddb,
+		codec: msgpack.NewCodec(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(db); err != nil {
+			return nil, err
+		}
+	}
+
+	// Whichever codec ends up selected above - the msgpack default, or
+	// one passed through WithCodec - must be checked against the
+	// database's own metadata here, not only when WithCodec happens to
+	// be used. Otherwise reopening a database with the default codec
+	// after it was created with a different one returns garbage
+	// documents instead of failing fast.
+	if err := database.CheckCodec(db.ng, db.codec); err != nil {
+		return nil, err
+	}
+
+	q, err := enginequeue.New(ng)
+	if err != nil {
+		return nil, err
+	}
+	db.indexBuildQueue = q
+	db.db.SetIndexBuildQueue(db.indexBuildQueue)
+
+	db.indexBuilder = database.NewIndexBuilder(db.db, db.indexBuildQueue)
+	if err := db.indexBuilder.Start(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Close releases every resource held by db, including stopping the
+// background index builder started by New.
+func (db *DB) Close() error {
+	db.indexBuilder.Stop()
+	return db.ng.Close()
+}
@@ -0,0 +1,39 @@
+package document
+
+// Array represents a sequence of values, analogous to a JSON array.
+type Array interface {
+	// Iterate goes through all the values of the array and calls the
+	// given function by passing each one of them. If the given function
+	// returns an error, the iteration stops.
+	Iterate(fn func(i int, value Value) error) error
+	// GetByIndex returns the value of the given index. It must return
+	// ErrValueNotFound if the index is out of range.
+	GetByIndex(i int) (Value, error)
+}
+
+// ValueBuffer stores a group of values in memory. It implements the
+// Array interface.
+type ValueBuffer []Value
+
+// Append a value to the buffer.
+func (vb ValueBuffer) Append(v Value) ValueBuffer {
+	return append(vb, v)
+}
+
+// Iterate implements the Array interface.
+func (vb ValueBuffer) Iterate(fn func(i int, value Value) error) error {
+	for i, v := range vb {
+		if err := fn(i, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByIndex implements the Array interface.
+func (vb ValueBuffer) GetByIndex(i int) (Value, error) {
+	if i < 0 || i >= len(vb) {
+		return Value{}, ErrValueNotFound
+	}
+	return vb[i], nil
+}
@@ -0,0 +1,120 @@
+package document
+
+import "errors"
+
+// ErrFieldNotFound is returned when a field is not found in a document.
+var ErrFieldNotFound = errors.New("field not found")
+
+// ErrValueNotFound is returned when an index is out of range in an array.
+var ErrValueNotFound = errors.New("value not found")
+
+// Document represents a group of key value pairs, analogous to a JSON
+// object.
+type Document interface {
+	// Iterate goes through all the fields of the document and calls the
+	// given function by passing each one of them. If the given function
+	// returns an error, the iteration stops.
+	Iterate(fn func(field string, value Value) error) error
+	// GetByField returns the value of the given field. It must return
+	// ErrFieldNotFound if the field doesn't exist.
+	GetByField(field string) (Value, error)
+}
+
+// Scan copies d's fields, in iteration order, into the addresses pointed
+// to by targets. It is mainly used to read rows returned by a query into
+// plain Go variables, e.g. document.Scan(d, &id, &name).
+func Scan(d Document, targets ...interface{}) error {
+	i := 0
+	return d.Iterate(func(field string, v Value) error {
+		if i >= len(targets) {
+			return nil
+		}
+
+		switch t := targets[i].(type) {
+		case *string:
+			s, err := v.ConvertToText()
+			if err != nil {
+				return err
+			}
+			*t = s
+		case *int64:
+			iv, err := v.ConvertTo(IntegerValue)
+			if err != nil {
+				return err
+			}
+			*t = iv.V.(int64)
+		case *bool:
+			*t = v.V.(bool)
+		case *Value:
+			*t = v
+		}
+
+		i++
+		return nil
+	})
+}
+
+// FieldBuffer stores a group of fields in memory. It implements the
+// Document interface.
+type FieldBuffer struct {
+	fields []fieldValue
+}
+
+type fieldValue struct {
+	Field string
+	Value Value
+}
+
+// Add a field to the buffer.
+func (fb *FieldBuffer) Add(field string, v Value) *FieldBuffer {
+	fb.fields = append(fb.fields, fieldValue{Field: field, Value: v})
+	return fb
+}
+
+// Iterate implements the Document interface.
+func (fb *FieldBuffer) Iterate(fn func(field string, value Value) error) error {
+	for _, f := range fb.fields {
+		if err := fn(f.Field, f.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByField implements the Document interface.
+func (fb *FieldBuffer) GetByField(field string) (Value, error) {
+	for _, f := range fb.fields {
+		if f.Field == field {
+			return f.Value, nil
+		}
+	}
+	return Value{}, ErrFieldNotFound
+}
+
+// Len returns the number of fields in the buffer.
+func (fb *FieldBuffer) Len() int {
+	return len(fb.fields)
+}
+
+// Length returns the number of fields in d. It iterates through d, so
+// prefer a type-specific Len method (e.g. FieldBuffer.Len) when d's
+// concrete type is known, to avoid the full iteration.
+func Length(d Document) (int, error) {
+	var n int
+	err := d.Iterate(func(string, Value) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// ArrayLength returns the number of values in a. It iterates through a,
+// so prefer len(vb) directly when a's concrete type is a ValueBuffer.
+func ArrayLength(a Array) (int, error) {
+	var n int
+	err := a.Iterate(func(int, Value) error {
+		n++
+		return nil
+	})
+	return n, err
+}
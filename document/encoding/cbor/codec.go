@@ -0,0 +1,297 @@
+// Package cbor implements a CBOR (RFC 7049) encoding.Codec, as an
+// alternative to the default msgpack codec.
+package cbor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/document/encoding"
+)
+
+// DurationTag is the CBOR tag number used to mark an encoded
+// document.DurationValue. It is picked from the 24-255 range reserved
+// for private use, the same trick the msgpack codec uses with its own
+// DurationType extension code.
+const DurationTag = 101
+
+// DocumentTag marks an encoded document.Document as an ordered sequence
+// of fields rather than a Go map, whose iteration order is unspecified
+// and varies from run to run. Without it, EncodeDocument round-tripped
+// through map[string]cbor.RawMessage on both ends and lost field order;
+// this tag lets EncodedDocument decode the same ordered array
+// EncodeDocument wrote, the way the msgpack codec preserves order by
+// construction (it streams fields in Iterate order instead of going
+// through a map at all).
+const DocumentTag = 102
+
+// Name is the identifier stored in the engine's metadata to record that
+// a database was encoded with this codec.
+const Name = "cbor"
+
+// A Codec is a CBOR implementation of an encoding.Codec.
+type Codec struct{}
+
+// NewCodec creates a CBOR codec.
+func NewCodec() Codec {
+	return Codec{}
+}
+
+// Name implements the database.namedCodec interface.
+func (c Codec) Name() string {
+	return Name
+}
+
+// NewEncoder implements the encoding.Codec interface.
+func (c Codec) NewEncoder(w io.Writer) encoding.Encoder {
+	return NewEncoder(w)
+}
+
+// NewDocument implements the encoding.Codec interface.
+func (c Codec) NewDocument(data []byte) document.Document {
+	return EncodedDocument(data)
+}
+
+var encMode = func() cbor.EncMode {
+	mode, err := cbor.EncOptions{}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+var decMode = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// Encoder encodes Genji documents and values in CBOR.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder creates an Encoder that writes in the given writer.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+func (e *Encoder) write(v interface{}) error {
+	data, err := encMode.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+// EncodeDocument encodes d as a DocumentTag-wrapped array of alternating
+// field name and value items, in the order d.Iterate produces them. A
+// bare CBOR map would lose that order: Go map iteration order is
+// unspecified, so both encoding a map[string]... and decoding into one
+// would reorder fields on every run.
+func (e *Encoder) EncodeDocument(d document.Document) error {
+	var items []cbor.RawMessage
+
+	err := d.Iterate(func(f string, v document.Value) error {
+		key, err := encMode.Marshal(f)
+		if err != nil {
+			return err
+		}
+		items = append(items, key)
+
+		var buf bytes.Buffer
+		if err := (&Encoder{w: &buf}).EncodeValue(v); err != nil {
+			return err
+		}
+		items = append(items, buf.Bytes())
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	content, err := encMode.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	return e.write(cbor.RawTag{Number: DocumentTag, Content: content})
+}
+
+// EncodeArray encodes a as a CBOR array.
+func (e *Encoder) EncodeArray(a document.Array) error {
+	var items []cbor.RawMessage
+
+	err := a.Iterate(func(i int, v document.Value) error {
+		var buf bytes.Buffer
+		if err := (&Encoder{w: &buf}).EncodeValue(v); err != nil {
+			return err
+		}
+
+		items = append(items, buf.Bytes())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.write(items)
+}
+
+// EncodeValue encodes v based on its type.
+// - document -> map
+// - array -> array
+// - NULL -> nil
+// - text -> text string
+// - blob -> byte string
+// - bool -> bool
+// - integer -> int64
+// - double -> float64
+// - duration -> tagged int64 of nanoseconds, using DurationTag
+func (e *Encoder) EncodeValue(v document.Value) error {
+	switch v.Type {
+	case document.DocumentValue:
+		return e.EncodeDocument(v.V.(document.Document))
+	case document.ArrayValue:
+		return e.EncodeArray(v.V.(document.Array))
+	case document.NullValue:
+		return e.write(nil)
+	case document.TextValue:
+		return e.write(v.V.(string))
+	case document.BlobValue:
+		return e.write(v.V.([]byte))
+	case document.BoolValue:
+		return e.write(v.V.(bool))
+	case document.IntegerValue:
+		return e.write(v.V.(int64))
+	case document.DoubleValue:
+		return e.write(v.V.(float64))
+	case document.DurationValue:
+		return e.write(cbor.Tag{
+			Number:  DurationTag,
+			Content: int64(v.V.(time.Duration)),
+		})
+	}
+
+	return e.write(v.V)
+}
+
+// Close is a no-op, kept so Encoder satisfies the same shape as the
+// msgpack Encoder.
+func (e *Encoder) Close() {}
+
+// Decoder decodes Genji documents and values from CBOR.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder creates a Decoder that reads from the given reader.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// DecodeValue reads one value from the reader and decodes it.
+func (d *Decoder) DecodeValue() (document.Value, error) {
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return document.Value{}, err
+	}
+
+	return decodeRaw(data)
+}
+
+func decodeRaw(data []byte) (document.Value, error) {
+	// Probed via cbor.RawTag, not cbor.Tag: cbor.Tag's Content is
+	// interface{}, so unmarshaling into it would eagerly decode the whole
+	// tagged payload through the generic path below just to read the tag
+	// number, defeating the laziness EncodedDocument relies on for
+	// DocumentTag. RawTag keeps Content as undecoded bytes instead.
+	var rt cbor.RawTag
+	if err := decMode.Unmarshal(data, &rt); err == nil {
+		switch rt.Number {
+		case DurationTag:
+			var n int64
+			if err := decMode.Unmarshal(rt.Content, &n); err != nil {
+				return document.Value{}, fmt.Errorf("cbor: malformed duration tag")
+			}
+			return document.NewDurationValue(time.Duration(n)), nil
+		case DocumentTag:
+			// EncodedDocument re-derives the field array itself (via
+			// cbor.RawTag, which keeps each item's raw bytes instead of
+			// decoding them through the lossy generic path below), so
+			// the only thing needed here is the original tagged bytes.
+			return document.NewDocumentValue(EncodedDocument(data)), nil
+		}
+	}
+
+	var raw interface{}
+	if err := decMode.Unmarshal(data, &raw); err != nil {
+		return document.Value{}, err
+	}
+
+	return fromGo(raw)
+}
+
+func fromGo(raw interface{}) (document.Value, error) {
+	switch t := raw.(type) {
+	case nil:
+		return document.NewNullValue(), nil
+	case string:
+		return document.NewTextValue(t), nil
+	case []byte:
+		return document.NewBlobValue(t), nil
+	case bool:
+		return document.NewBoolValue(t), nil
+	case int64:
+		return document.NewIntegerValue(t), nil
+	case uint64:
+		return document.NewIntegerValue(int64(t)), nil
+	case float64:
+		return document.NewDoubleValue(t), nil
+	case []interface{}:
+		var vb document.ValueBuffer
+		for _, item := range t {
+			v, err := fromGo(item)
+			if err != nil {
+				return document.Value{}, err
+			}
+			vb = vb.Append(v)
+		}
+		return document.NewArrayValue(vb), nil
+	}
+
+	return document.Value{}, fmt.Errorf("cbor: unsupported type %T", raw)
+}
+
+// DecodeDocument decodes one document from the reader.
+func (d *Decoder) DecodeDocument() (document.Document, error) {
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodedDocument(data), nil
+}
+
+// DecodeArray decodes one array from the reader.
+func (d *Decoder) DecodeArray() (document.Array, error) {
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodedArray(data), nil
+}
+
+// Close is a no-op, kept so Decoder satisfies the same shape as the
+// msgpack Decoder.
+func (d *Decoder) Close() {}
@@ -0,0 +1,124 @@
+package cbor
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/genjidb/genji/document"
+)
+
+// EncodedDocument implements the document.Document interface on top of
+// CBOR encoded data produced by Encoder.EncodeDocument: a DocumentTag-
+// wrapped array of alternating field name and value items, in the order
+// Iterate originally produced them. It decodes lazily, on every call to
+// Iterate or GetByField, so that reading a single field out of a large
+// document doesn't require decoding the rest of it.
+type EncodedDocument []byte
+
+// items splits the document back into its raw field name / value items,
+// using cbor.RawTag (rather than cbor.Tag) so each item keeps its
+// original encoded bytes instead of being decoded through the generic,
+// order-losing interface{} path.
+func (e EncodedDocument) items() ([]cbor.RawMessage, error) {
+	var rt cbor.RawTag
+	if err := decMode.Unmarshal(e, &rt); err != nil {
+		return nil, err
+	}
+	if rt.Number != DocumentTag {
+		return nil, fmt.Errorf("cbor: not a document")
+	}
+
+	var items []cbor.RawMessage
+	if err := decMode.Unmarshal(rt.Content, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Iterate decodes each field of the document and calls fn with it, in
+// the order they were encoded.
+func (e EncodedDocument) Iterate(fn func(field string, value document.Value) error) error {
+	items, err := e.items()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(items); i += 2 {
+		var f string
+		if err := decMode.Unmarshal(items[i], &f); err != nil {
+			return err
+		}
+
+		v, err := decodeRaw(items[i+1])
+		if err != nil {
+			return err
+		}
+
+		if err := fn(f, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByField decodes and returns the value of the given field.
+func (e EncodedDocument) GetByField(field string) (document.Value, error) {
+	items, err := e.items()
+	if err != nil {
+		return document.Value{}, err
+	}
+
+	for i := 0; i+1 < len(items); i += 2 {
+		var f string
+		if err := decMode.Unmarshal(items[i], &f); err != nil {
+			return document.Value{}, err
+		}
+		if f == field {
+			return decodeRaw(items[i+1])
+		}
+	}
+
+	return document.Value{}, document.ErrFieldNotFound
+}
+
+// EncodedArray implements the document.Array interface on top of CBOR
+// encoded data, decoding items lazily the same way EncodedDocument does
+// for fields.
+type EncodedArray []byte
+
+// Iterate decodes each item of the array and calls fn with it.
+func (e EncodedArray) Iterate(fn func(i int, value document.Value) error) error {
+	var items []cbor.RawMessage
+	if err := decMode.Unmarshal(e, &items); err != nil {
+		return err
+	}
+
+	for i, raw := range items {
+		v, err := decodeRaw(raw)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(i, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByIndex decodes and returns the value at the given index.
+func (e EncodedArray) GetByIndex(i int) (document.Value, error) {
+	var items []cbor.RawMessage
+	if err := decMode.Unmarshal(e, &items); err != nil {
+		return document.Value{}, err
+	}
+
+	if i < 0 || i >= len(items) {
+		return document.Value{}, document.ErrValueNotFound
+	}
+
+	return decodeRaw(items[i])
+}
@@ -0,0 +1,28 @@
+// Package encoding defines the Codec abstraction a database uses to
+// serialize documents to and from the underlying engine.Store, so the
+// wire format (msgpack, cbor, ...) can be swapped without touching the
+// rest of genji.
+package encoding
+
+import (
+	"io"
+
+	"github.com/genjidb/genji/document"
+)
+
+// Codec is implemented by every document encoding genji supports.
+type Codec interface {
+	// NewEncoder returns an Encoder writing to w.
+	NewEncoder(w io.Writer) Encoder
+	// NewDocument wraps data, previously produced by an Encoder of this
+	// Codec, into a lazily-decoded document.Document.
+	NewDocument(data []byte) document.Document
+}
+
+// Encoder encodes documents and values into a Codec's wire format.
+type Encoder interface {
+	EncodeDocument(d document.Document) error
+	EncodeArray(a document.Array) error
+	EncodeValue(v document.Value) error
+	Close()
+}
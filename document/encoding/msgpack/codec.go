@@ -16,6 +16,10 @@ const (
 	DurationType int8 = 0x1
 )
 
+// Name is the identifier stored in the engine's metadata to record that
+// a database was encoded with this codec.
+const Name = "msgpack"
+
 // A Codec is a MessagePack implementation of an encoding.Codec.
 type Codec struct{}
 
@@ -24,6 +28,11 @@ func NewCodec() Codec {
 	return Codec{}
 }
 
+// Name implements the database.namedCodec interface.
+func (c Codec) Name() string {
+	return Name
+}
+
 // NewEncoder implements the encoding.Codec interface.
 func (c Codec) NewEncoder(w io.Writer) encoding.Encoder {
 	return NewEncoder(w)
@@ -0,0 +1,109 @@
+package msgpack
+
+import (
+	"bytes"
+
+	"github.com/genjidb/genji/document"
+)
+
+// EncodedDocument implements the document.Document interface on top of
+// MessagePack encoded data. It decodes its fields lazily, on every call
+// to Iterate or GetByField, so that reading a single field out of a
+// large document doesn't require decoding the rest of it.
+type EncodedDocument []byte
+
+// Iterate decodes each field of the document and calls fn with it.
+func (e EncodedDocument) Iterate(fn func(field string, value document.Value) error) error {
+	dec := NewDecoder(bytes.NewReader(e))
+	defer dec.Close()
+
+	l, err := dec.dec.DecodeMapLen()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < l; i++ {
+		f, err := dec.dec.DecodeString()
+		if err != nil {
+			return err
+		}
+
+		v, err := dec.DecodeValue()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(f, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByField decodes and returns the value of the given field.
+func (e EncodedDocument) GetByField(field string) (document.Value, error) {
+	var v document.Value
+	found := document.ErrFieldNotFound
+
+	err := e.Iterate(func(f string, value document.Value) error {
+		if f == field {
+			v = value
+			found = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return document.Value{}, err
+	}
+
+	return v, found
+}
+
+// EncodedArray implements the document.Array interface on top of
+// MessagePack encoded data, decoding items lazily the same way
+// EncodedDocument does for fields.
+type EncodedArray []byte
+
+// Iterate decodes each item of the array and calls fn with it.
+func (e EncodedArray) Iterate(fn func(i int, value document.Value) error) error {
+	dec := NewDecoder(bytes.NewReader(e))
+	defer dec.Close()
+
+	l, err := dec.dec.DecodeArrayLen()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < l; i++ {
+		v, err := dec.DecodeValue()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(i, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByIndex decodes and returns the value at the given index.
+func (e EncodedArray) GetByIndex(i int) (document.Value, error) {
+	var v document.Value
+	found := document.ErrValueNotFound
+
+	err := e.Iterate(func(idx int, value document.Value) error {
+		if idx == i {
+			v = value
+			found = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return document.Value{}, err
+	}
+
+	return v, found
+}
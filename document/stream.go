@@ -0,0 +1,26 @@
+package document
+
+// An Iterator can iterate over documents.
+type Iterator interface {
+	Iterate(fn func(d Document) error) error
+}
+
+// Stream wraps an Iterator so that query execution always manipulates
+// the same type, regardless of whether the documents come from a table
+// scan, an index iterator or a full-text search iterator.
+type Stream struct {
+	it Iterator
+}
+
+// NewStream creates a Stream from it.
+func NewStream(it Iterator) Stream {
+	return Stream{it: it}
+}
+
+// Iterate calls fn for each document of the stream.
+func (s Stream) Iterate(fn func(d Document) error) error {
+	if s.it == nil {
+		return nil
+	}
+	return s.it.Iterate(fn)
+}
@@ -0,0 +1,142 @@
+// Package document defines the document and value model shared by every
+// genji component that reads or writes rows: the storage engine, the
+// document codecs and the query layer all exchange data as Document and
+// Value instead of depending on each other's concrete types.
+package document
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValueType represents the type of a value.
+type ValueType uint8
+
+// List of supported value types.
+const (
+	NullValue ValueType = iota
+	BoolValue
+	IntegerValue
+	DoubleValue
+	BlobValue
+	TextValue
+	ArrayValue
+	DocumentValue
+	DurationValue
+)
+
+// Float64Value is kept as an alias of DoubleValue for call sites written
+// before the type was renamed.
+const Float64Value = DoubleValue
+
+// IsNumber returns true if t is either IntegerValue or DoubleValue.
+func (t ValueType) IsNumber() bool {
+	return t == IntegerValue || t == DoubleValue
+}
+
+func (t ValueType) String() string {
+	switch t {
+	case NullValue:
+		return "null"
+	case BoolValue:
+		return "bool"
+	case IntegerValue:
+		return "integer"
+	case DoubleValue:
+		return "double"
+	case BlobValue:
+		return "blob"
+	case TextValue:
+		return "text"
+	case ArrayValue:
+		return "array"
+	case DocumentValue:
+		return "document"
+	case DurationValue:
+		return "duration"
+	}
+	return "unknown"
+}
+
+// Value represents a value of a certain type, along with its Go
+// representation in V.
+type Value struct {
+	Type ValueType
+	V    interface{}
+}
+
+// NewNullValue creates a null value.
+func NewNullValue() Value {
+	return Value{Type: NullValue}
+}
+
+// NewBoolValue creates a value of type BoolValue.
+func NewBoolValue(v bool) Value {
+	return Value{Type: BoolValue, V: v}
+}
+
+// NewIntegerValue creates a value of type IntegerValue.
+func NewIntegerValue(v int64) Value {
+	return Value{Type: IntegerValue, V: v}
+}
+
+// NewDoubleValue creates a value of type DoubleValue.
+func NewDoubleValue(v float64) Value {
+	return Value{Type: DoubleValue, V: v}
+}
+
+// NewBlobValue creates a value of type BlobValue.
+func NewBlobValue(v []byte) Value {
+	return Value{Type: BlobValue, V: v}
+}
+
+// NewTextValue creates a value of type TextValue.
+func NewTextValue(v string) Value {
+	return Value{Type: TextValue, V: v}
+}
+
+// NewArrayValue creates a value of type ArrayValue.
+func NewArrayValue(a Array) Value {
+	return Value{Type: ArrayValue, V: a}
+}
+
+// NewDocumentValue creates a value of type DocumentValue.
+func NewDocumentValue(d Document) Value {
+	return Value{Type: DocumentValue, V: d}
+}
+
+// NewDurationValue creates a value of type DurationValue.
+func NewDurationValue(v time.Duration) Value {
+	return Value{Type: DurationValue, V: v}
+}
+
+// ConvertTo converts v to the given type, when the conversion is
+// unambiguous. It currently only supports numeric widening, which is all
+// the query layer needs to compare an index key against a literal.
+func (v Value) ConvertTo(t ValueType) (Value, error) {
+	if v.Type == t {
+		return v, nil
+	}
+
+	if t == DoubleValue {
+		switch n := v.V.(type) {
+		case int64:
+			return NewDoubleValue(float64(n)), nil
+		case float64:
+			return NewDoubleValue(n), nil
+		}
+	}
+
+	return Value{}, fmt.Errorf("cannot convert %s to %s", v.Type, t)
+}
+
+// ConvertToText returns the textual representation of v, failing if v is
+// not a TextValue. This is what every MATCH/search query string goes
+// through before being handed to a FullTextIndex.
+func (v Value) ConvertToText() (string, error) {
+	s, ok := v.V.(string)
+	if !ok {
+		return "", fmt.Errorf("cannot convert %s to text", v.Type)
+	}
+	return s, nil
+}
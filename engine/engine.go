@@ -0,0 +1,69 @@
+// Package engine defines the low-level, key/value storage abstraction
+// that the database package builds tables, indexes and catalog storage
+// on top of. Concrete implementations live in their own sub-packages
+// (badgerengine, memengine) so the rest of genji never depends on a
+// particular storage backend.
+package engine
+
+// Engine represents the underlying storage engine a genji database
+// persists data to. Stores created through a transaction are durable
+// across Engine restarts; metadata keys set through SetMetadata are
+// genji's own bookkeeping, separate from any store.
+type Engine interface {
+	// Begin starts a new transaction. A writable transaction can create
+	// stores and write to them; a read-only transaction only reads.
+	Begin(writable bool) (Transaction, error)
+	// GetMetadata returns the value stored under key, or
+	// ErrMetadataNotFound if it was never set.
+	GetMetadata(key string) ([]byte, error)
+	// SetMetadata stores value under key.
+	SetMetadata(key string, value []byte) error
+	// Close releases every resource held by the engine.
+	Close() error
+}
+
+// Transaction represents a transaction against the engine. Stores
+// fetched or created through it are only durable once Commit succeeds.
+type Transaction interface {
+	// GetStore returns the store with the given name, or
+	// ErrStoreNotFound if it doesn't exist.
+	GetStore(name []byte) (Store, error)
+	// CreateStore creates a store with the given name.
+	CreateStore(name []byte) error
+	// DropStore deletes a store and all of its keys.
+	DropStore(name []byte) error
+	// Commit commits the transaction. It becomes a no-op once called.
+	Commit() error
+	// Rollback rolls back the transaction. It is always safe to call,
+	// including after a successful Commit, in which case it is a no-op.
+	Rollback() error
+}
+
+// Store represents a key/value store.
+type Store interface {
+	Put(k, v []byte) error
+	Get(k []byte) ([]byte, error)
+	Delete(k []byte) error
+	NewIterator(opts IteratorOptions) Iterator
+}
+
+// IteratorOptions configures the direction an Iterator walks a Store in.
+type IteratorOptions struct {
+	Reverse bool
+}
+
+// Iterator iterates over the key/value pairs of a Store in key order (or
+// reverse key order, if created with IteratorOptions.Reverse).
+type Iterator interface {
+	Seek(key []byte)
+	Valid() bool
+	Next()
+	Item() Item
+	Close() error
+}
+
+// Item is a single key/value pair read from an Iterator.
+type Item interface {
+	Key() []byte
+	ValueCopy(dst []byte) ([]byte, error)
+}
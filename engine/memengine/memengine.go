@@ -0,0 +1,209 @@
+// Package memengine implements an in-memory engine.Engine, for tests and
+// other situations where durability across restarts is not needed. It is
+// to engine.Engine what queue.MemQueue is to queue.Queue.
+package memengine
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/engine"
+)
+
+// Engine is an in-memory engine.Engine backed by a map of stores, each
+// itself a map of keys to values.
+type Engine struct {
+	mu       sync.Mutex
+	stores   map[string]map[string][]byte
+	metadata map[string][]byte
+}
+
+// NewEngine creates an empty in-memory engine.
+func NewEngine() *Engine {
+	return &Engine{
+		stores:   make(map[string]map[string][]byte),
+		metadata: make(map[string][]byte),
+	}
+}
+
+var _ engine.Engine = (*Engine)(nil)
+
+// Begin implements the engine.Engine interface. Since the in-memory
+// engine has no WAL to isolate concurrent transactions against, Begin
+// takes the engine-wide lock for the lifetime of the transaction: good
+// enough for tests, not for production use.
+func (e *Engine) Begin(writable bool) (engine.Transaction, error) {
+	e.mu.Lock()
+	return &tx{engine: e}, nil
+}
+
+// GetMetadata implements the engine.Engine interface.
+func (e *Engine) GetMetadata(key string) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	v, ok := e.metadata[key]
+	if !ok {
+		return nil, database.ErrMetadataNotFound
+	}
+	return v, nil
+}
+
+// SetMetadata implements the engine.Engine interface.
+func (e *Engine) SetMetadata(key string, value []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.metadata[key] = value
+	return nil
+}
+
+// Close implements the engine.Engine interface.
+func (e *Engine) Close() error {
+	return nil
+}
+
+type tx struct {
+	engine   *Engine
+	done     bool
+}
+
+func (t *tx) end() {
+	if !t.done {
+		t.done = true
+		t.engine.mu.Unlock()
+	}
+}
+
+func (t *tx) GetStore(name []byte) (engine.Store, error) {
+	s, ok := t.engine.stores[string(name)]
+	if !ok {
+		return nil, errStoreNotFound
+	}
+	return &store{m: s}, nil
+}
+
+func (t *tx) CreateStore(name []byte) error {
+	t.engine.stores[string(name)] = make(map[string][]byte)
+	return nil
+}
+
+func (t *tx) DropStore(name []byte) error {
+	delete(t.engine.stores, string(name))
+	return nil
+}
+
+func (t *tx) Commit() error {
+	t.end()
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	t.end()
+	return nil
+}
+
+var errStoreNotFound = storeNotFound{}
+
+type storeNotFound struct{}
+
+func (storeNotFound) Error() string { return "store not found" }
+
+type store struct {
+	m map[string][]byte
+}
+
+func (s *store) Put(k, v []byte) error {
+	cp := make([]byte, len(v))
+	copy(cp, v)
+	s.m[string(k)] = cp
+	return nil
+}
+
+func (s *store) Get(k []byte) ([]byte, error) {
+	v, ok := s.m[string(k)]
+	if !ok {
+		return nil, errKeyNotFound
+	}
+	return v, nil
+}
+
+func (s *store) Delete(k []byte) error {
+	delete(s.m, string(k))
+	return nil
+}
+
+var errKeyNotFound = keyNotFound{}
+
+type keyNotFound struct{}
+
+func (keyNotFound) Error() string { return "key not found" }
+
+func (s *store) NewIterator(opts engine.IteratorOptions) engine.Iterator {
+	keys := make([]string, 0, len(s.m))
+	for k := range s.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if opts.Reverse {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	return &iterator{store: s, keys: keys, reverse: opts.Reverse, pos: -1}
+}
+
+type iterator struct {
+	store   *store
+	keys    []string
+	reverse bool
+	pos     int
+}
+
+func (it *iterator) Seek(key []byte) {
+	target := string(key)
+	for i, k := range it.keys {
+		if it.reverse {
+			if k <= target || len(target) == 0 {
+				it.pos = i
+				return
+			}
+		} else if k >= target {
+			it.pos = i
+			return
+		}
+	}
+	it.pos = len(it.keys)
+}
+
+func (it *iterator) Valid() bool {
+	return it.pos >= 0 && it.pos < len(it.keys)
+}
+
+func (it *iterator) Next() {
+	it.pos++
+}
+
+func (it *iterator) Item() engine.Item {
+	return item{key: it.keys[it.pos], store: it.store}
+}
+
+func (it *iterator) Close() error {
+	return nil
+}
+
+type item struct {
+	key   string
+	store *store
+}
+
+func (i item) Key() []byte {
+	return []byte(i.key)
+}
+
+func (i item) ValueCopy(dst []byte) ([]byte, error) {
+	v := i.store.m[i.key]
+	return append(dst[:0], v...), nil
+}
@@ -0,0 +1,149 @@
+// Package bleveindex implements a github.com/genjidb/genji/index.FullTextIndex
+// backed by bleve (github.com/blevesearch/bleve).
+package bleveindex
+
+import (
+	"encoding/hex"
+
+	"github.com/blevesearch/bleve"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/index"
+)
+
+// document indexed by bleve for a single genji value. Bleve indexes Go
+// structs by reflection, so the text is stored under its own field rather
+// than indexing v.V directly.
+type indexedText struct {
+	Text string `json:"text"`
+}
+
+// Index is a FullTextIndex that stores its inverted index using bleve.
+// Keys are hex-encoded before being used as bleve document ids, since
+// genji keys may contain bytes that are not valid UTF-8.
+type Index struct {
+	idx   bleve.Index
+	batch *bleve.Batch
+}
+
+// New creates a bleve-backed full-text index at path. If path is empty,
+// an in-memory index is created.
+func New(path string) (*Index, error) {
+	mapping := bleve.NewIndexMapping()
+
+	var bidx bleve.Index
+	var err error
+	if path == "" {
+		bidx, err = bleve.NewMemOnly(mapping)
+	} else {
+		bidx, err = bleve.Open(path)
+		if err != nil {
+			bidx, err = bleve.New(path, mapping)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Index{idx: bidx}, nil
+}
+
+var _ index.FullTextIndex = (*Index)(nil)
+
+func docID(key []byte) string {
+	return hex.EncodeToString(key)
+}
+
+// Set implements the index.FullTextIndex interface.
+func (i *Index) Set(key []byte, v document.Value) error {
+	text, err := v.ConvertToText()
+	if err != nil {
+		return err
+	}
+
+	if i.batch != nil {
+		return i.batch.Index(docID(key), indexedText{Text: text})
+	}
+
+	return i.idx.Index(docID(key), indexedText{Text: text})
+}
+
+// Delete implements the index.FullTextIndex interface.
+func (i *Index) Delete(key []byte) error {
+	if i.batch != nil {
+		i.batch.Delete(docID(key))
+		return nil
+	}
+
+	return i.idx.Delete(docID(key))
+}
+
+// searchPageSize is how many hits Search requests per page. bleve
+// defaults a SearchRequest to 10 hits, which would silently truncate
+// every match set bigger than that; paginating with From/Size instead
+// of relying on the default is what makes Search report every match,
+// not just the first page of them.
+const searchPageSize = 1000
+
+// Search implements the index.FullTextIndex interface.
+func (i *Index) Search(q string, fn func(key []byte, score float64) error) error {
+	query := bleve.NewMatchQuery(q)
+
+	for from := 0; ; from += searchPageSize {
+		req := bleve.NewSearchRequestOptions(query, searchPageSize, from, false)
+
+		res, err := i.idx.Search(req)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range res.Hits {
+			key, err := hex.DecodeString(hit.ID)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(key, hit.Score); err != nil {
+				return err
+			}
+		}
+
+		if uint64(from+len(res.Hits)) >= res.Total {
+			return nil
+		}
+	}
+}
+
+// Truncate implements the index.FullTextIndex interface.
+func (i *Index) Truncate() error {
+	return i.idx.DeleteIndex()
+}
+
+// Close implements the index.FullTextIndex interface.
+func (i *Index) Close() error {
+	return i.idx.Close()
+}
+
+// StartBatch opens a bleve batch that buffers Set and Delete calls until
+// FlushBatch is called. database.Transaction uses this so that document
+// rewrites only become visible to Search once the enclosing transaction
+// commits.
+func (i *Index) StartBatch() {
+	i.batch = i.idx.NewBatch()
+}
+
+// FlushBatch applies the buffered batch to the underlying bleve index.
+func (i *Index) FlushBatch() error {
+	if i.batch == nil {
+		return nil
+	}
+
+	b := i.batch
+	i.batch = nil
+	return i.idx.Batch(b)
+}
+
+// DiscardBatch drops the buffered batch without applying it. Called on
+// transaction rollback.
+func (i *Index) DiscardBatch() {
+	i.batch = nil
+}
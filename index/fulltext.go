@@ -0,0 +1,28 @@
+package index
+
+import (
+	"github.com/genjidb/genji/document"
+)
+
+// FullTextIndex is implemented by full-text search backends that can index
+// and query text values stored at a given document path. Unlike the
+// comparison-based Index interface, a FullTextIndex tokenizes the indexed
+// text and matches on relevance rather than equality or range.
+//
+// Implementations are expected to be safe for use within a single
+// database.Transaction: Set and Delete calls made during a transaction
+// must only become visible to Search once that transaction commits, and
+// must be entirely discarded on rollback.
+type FullTextIndex interface {
+	// Set indexes v, a text value, under key.
+	Set(key []byte, v document.Value) error
+	// Delete removes the tokens associated with key from the index.
+	Delete(key []byte) error
+	// Search runs query against the index and calls fn with the key of
+	// every matching document, ordered by decreasing relevance score.
+	Search(query string, fn func(key []byte, score float64) error) error
+	// Truncate drops every entry from the index.
+	Truncate() error
+	// Close releases any resources held by the index.
+	Close() error
+}
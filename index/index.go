@@ -0,0 +1,25 @@
+// Package index defines the index abstractions a database.Table can be
+// queried through: Index for ordered, comparison-based lookups, and
+// FullTextIndex (see fulltext.go) for relevance-based text search.
+package index
+
+import "github.com/genjidb/genji/document"
+
+// Index is implemented by comparison-based indexes, associating an
+// ordered value to the key of the document it was taken from.
+type Index interface {
+	// Set associates v with key.
+	Set(v document.Value, key []byte) error
+	// Delete removes the entry associated with v and key.
+	Delete(v document.Value, key []byte) error
+	// AscendGreaterOrEqual iterates over the index in ascending order,
+	// starting at pivot (or from the beginning, if pivot is the zero
+	// Value), calling fn for every matching value and key.
+	AscendGreaterOrEqual(pivot document.Value, fn func(val document.Value, key []byte) error) error
+	// DescendLessOrEqual iterates over the index in descending order,
+	// starting at pivot (or from the end, if pivot is the zero Value),
+	// calling fn for every matching value and key.
+	DescendLessOrEqual(pivot document.Value, fn func(val document.Value, key []byte) error) error
+	// Truncate drops every entry from the index.
+	Truncate() error
+}
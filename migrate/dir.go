@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dir is a Source that loads migrations from a directory of .sql files.
+// Each file is expected to contain a "-- +migrate Up" section followed
+// by an optional "-- +migrate Down" section, e.g.:
+//
+//	-- +migrate Up
+//	CREATE TABLE users (id TEXT PRIMARY KEY);
+//
+//	-- +migrate Down
+//	DROP TABLE users;
+//
+// The migration ID is the file name without its .sql extension, so
+// files should be named so that they sort in the order they are meant
+// to be applied, e.g. 0001_create_users.sql.
+type Dir string
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+// Migrations implements the Source interface.
+func (d Dir) Migrations() ([]Migration, error) {
+	files, err := filepath.Glob(filepath.Join(string(d), "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	migrations := make([]Migration, 0, len(files))
+	for _, f := range files {
+		m, err := parseMigrationFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", f, err)
+		}
+
+		migrations = append(migrations, m)
+	}
+
+	return migrations, nil
+}
+
+func parseMigrationFile(path string) (Migration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Migration{}, err
+	}
+	defer f.Close()
+
+	id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var up, down strings.Builder
+	cur := &up
+	seenUp := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch strings.TrimSpace(line) {
+		case upMarker:
+			cur = &up
+			seenUp = true
+			continue
+		case downMarker:
+			cur = &down
+			continue
+		}
+
+		cur.WriteString(line)
+		cur.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return Migration{}, err
+	}
+
+	if !seenUp {
+		return Migration{}, fmt.Errorf("missing %q marker", upMarker)
+	}
+
+	return Migration{ID: id, Up: up.String(), Down: down.String()}, nil
+}
@@ -0,0 +1,51 @@
+package migrate_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genjidb/genji/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMigrationFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+	require.NoError(t, err)
+}
+
+func TestDirMigrations(t *testing.T) {
+	dir := t.TempDir()
+
+	writeMigrationFile(t, dir, "0002_add_age.sql", `-- +migrate Up
+ALTER TABLE users ADD FIELD age;
+-- +migrate Down
+ALTER TABLE users DROP FIELD age;
+`)
+	writeMigrationFile(t, dir, "0001_create_users.sql", `-- +migrate Up
+CREATE TABLE users (id TEXT PRIMARY KEY);
+`)
+
+	migrations, err := migrate.Dir(dir).Migrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	// Dir sorts files by name, not by the order they were written, so
+	// 0001 comes back before 0002 regardless of the order above.
+	require.Equal(t, "0001_create_users", migrations[0].ID)
+	require.Equal(t, "CREATE TABLE users (id TEXT PRIMARY KEY);\n", migrations[0].Up)
+	require.Equal(t, "", migrations[0].Down)
+
+	require.Equal(t, "0002_add_age", migrations[1].ID)
+	require.Equal(t, "ALTER TABLE users ADD FIELD age;\n", migrations[1].Up)
+	require.Equal(t, "ALTER TABLE users DROP FIELD age;\n", migrations[1].Down)
+}
+
+func TestDirMigrationsMissingUpMarker(t *testing.T) {
+	dir := t.TempDir()
+	writeMigrationFile(t, dir, "0001_bad.sql", "CREATE TABLE users (id TEXT PRIMARY KEY);\n")
+
+	_, err := migrate.Dir(dir).Migrations()
+	require.Error(t, err)
+}
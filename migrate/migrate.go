@@ -0,0 +1,312 @@
+// Package migrate implements a versioned schema migration system for
+// genji databases, modeled on the sql-migrate workflow: ordered .sql
+// files (or Go-registered migrations) are applied inside a single
+// genji.Tx each, and the set of applied versions is tracked in a
+// reserved __genji_migrations table.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/genjidb/genji"
+	"github.com/genjidb/genji/document"
+)
+
+// migrationsTable is the reserved table used to track which migrations
+// have already been applied.
+const migrationsTable = "__genji_migrations"
+
+// Migration is a single, ordered schema change. Up must be provided;
+// Down may be nil for migrations that are not meant to be reverted.
+type Migration struct {
+	// ID must sort lexically in the order migrations are meant to be
+	// applied, e.g. "0001_create_users".
+	ID   string
+	Up   string
+	Down string
+}
+
+// Source provides an ordered list of migrations. Source implementations
+// are provided for SQL files (Dir) and Go-registered migrations
+// (Migrations).
+type Source interface {
+	Migrations() ([]Migration, error)
+}
+
+// Migrations is a Source backed by a Go slice, for callers who prefer to
+// register migrations in code rather than load them from files.
+type Migrations []Migration
+
+// Migrations implements the Source interface.
+func (m Migrations) Migrations() ([]Migration, error) {
+	sorted := make([]Migration, len(m))
+	copy(sorted, m)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted, nil
+}
+
+// record is a row of the __genji_migrations table.
+type record struct {
+	ID        string
+	Name      string
+	AppliedAt time.Time
+	Checksum  string
+}
+
+func checksum(m Migration) string {
+	sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+	return hex.EncodeToString(sum[:])
+}
+
+// migrationName derives a human-readable name from a migration ID by
+// stripping the leading numeric ordering prefix Dir expects IDs to sort
+// by, e.g. "0001_create_users" becomes "create_users". IDs that don't
+// follow that convention are used as their own name.
+func migrationName(id string) string {
+	i := strings.IndexByte(id, '_')
+	if i < 0 {
+		return id
+	}
+
+	prefix := id[:i]
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return id
+		}
+	}
+
+	return id[i+1:]
+}
+
+func ensureMigrationsTable(db *genji.DB) error {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (id TEXT PRIMARY KEY, name TEXT, applied_at TEXT, checksum TEXT)",
+		migrationsTable,
+	))
+}
+
+// appliedRecords reads back every row of the migrations table. It reads
+// "name" by field rather than by the position document.Scan would
+// assume, since a database migrated from before this field existed has
+// rows with no "name" at all: ensureMigrationsTable's CREATE TABLE IF
+// NOT EXISTS is a no-op against an already-existing table, so those rows
+// never gain the column. migrationName(id) covers that gap.
+func appliedRecords(db *genji.DB) (map[string]record, error) {
+	applied := make(map[string]record)
+
+	res, err := db.Query(fmt.Sprintf("SELECT id, name, applied_at, checksum FROM %s", migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	err = res.Iterate(func(d document.Document) error {
+		var r record
+		id, err := d.GetByField("id")
+		if err != nil {
+			return err
+		}
+		r.ID, err = id.ConvertToText()
+		if err != nil {
+			return err
+		}
+
+		if v, err := d.GetByField("name"); err == nil {
+			r.Name, err = v.ConvertToText()
+			if err != nil {
+				return err
+			}
+		} else {
+			r.Name = migrationName(r.ID)
+		}
+
+		appliedAt, err := d.GetByField("applied_at")
+		if err != nil {
+			return err
+		}
+		t, err := time.Parse(time.RFC3339, appliedAt.V.(string))
+		if err != nil {
+			return err
+		}
+		r.AppliedAt = t
+
+		checksumVal, err := d.GetByField("checksum")
+		if err != nil {
+			return err
+		}
+		r.Checksum, err = checksumVal.ConvertToText()
+		if err != nil {
+			return err
+		}
+
+		applied[r.ID] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// Up applies every migration from src that has not already been applied,
+// in order. Each migration runs inside its own genji.Tx, so a failure
+// partway through a migration rolls that migration back cleanly without
+// affecting previously applied ones.
+func Up(db *genji.DB, src Source) error {
+	if err := ensureMigrationsTable(db); err != nil {
+		return err
+	}
+
+	migrations, err := src.Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedRecords(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		sum := checksum(m)
+
+		if r, ok := applied[m.ID]; ok {
+			if r.Checksum != sum {
+				return fmt.Errorf("migrate: checksum mismatch for already-applied migration %q", m.ID)
+			}
+			continue
+		}
+
+		err := db.Update(func(tx *genji.Tx) error {
+			for _, stmt := range splitStatements(m.Up) {
+				if err := tx.Exec(stmt); err != nil {
+					return fmt.Errorf("migrate: applying %q: %w", m.ID, err)
+				}
+			}
+
+			return tx.Exec(
+				fmt.Sprintf("INSERT INTO %s (id, name, applied_at, checksum) VALUES (?, ?, ?, ?)", migrationsTable),
+				m.ID, migrationName(m.ID), time.Now().Format(time.RFC3339), sum,
+			)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Down reverts the last n applied migrations from src, most recent
+// first. Each rollback runs inside its own genji.Tx.
+func Down(db *genji.DB, src Source, n int) error {
+	if err := ensureMigrationsTable(db); err != nil {
+		return err
+	}
+
+	migrations, err := src.Migrations()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]Migration, len(migrations))
+	for _, m := range migrations {
+		byID[m.ID] = m
+	}
+
+	applied, err := appliedRecords(db)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for id := range applied {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	if n < 0 {
+		n = 0
+	}
+	if n > len(ids) {
+		n = len(ids)
+	}
+
+	for _, id := range ids[:n] {
+		m, ok := byID[id]
+		if !ok || m.Down == "" {
+			return fmt.Errorf("migrate: no Down migration registered for %q", id)
+		}
+
+		err := db.Update(func(tx *genji.Tx) error {
+			for _, stmt := range splitStatements(m.Down) {
+				if err := tx.Exec(stmt); err != nil {
+					return fmt.Errorf("migrate: reverting %q: %w", id, err)
+				}
+			}
+
+			return tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", migrationsTable), id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrationStatus describes whether a single migration has been
+// applied.
+type MigrationStatus struct {
+	ID        string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Status returns the MigrationStatus of every migration in src, in order.
+func Status(db *genji.DB, src Source) ([]MigrationStatus, error) {
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	migrations, err := src.Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedRecords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		r, ok := applied[m.ID]
+		statuses = append(statuses, MigrationStatus{
+			ID:        m.ID,
+			Applied:   ok,
+			AppliedAt: r.AppliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// splitStatements splits a migration section into individual SQL
+// statements on ";" boundaries, dropping empty ones.
+func splitStatements(s string) []string {
+	var out []string
+	for _, stmt := range strings.Split(s, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt != "" {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
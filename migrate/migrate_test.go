@@ -0,0 +1,27 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/genjidb/genji/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+// Up, Down and Status all drive a *genji.DB through genji.Tx/db.Update, none
+// of which exist on the DB type in this tree yet, so they can't be exercised
+// here; this only covers the Source implementations, which don't depend on
+// genji.DB at all.
+func TestMigrationsSourceSortsByID(t *testing.T) {
+	src := migrate.Migrations{
+		{ID: "0002_add_age"},
+		{ID: "0001_create_users"},
+		{ID: "0003_add_index"},
+	}
+
+	migrations, err := src.Migrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	require.Equal(t, "0001_create_users", migrations[0].ID)
+	require.Equal(t, "0002_add_age", migrations[1].ID)
+	require.Equal(t, "0003_add_index", migrations[2].ID)
+}
@@ -0,0 +1,19 @@
+package genji
+
+import "github.com/genjidb/genji/document/encoding"
+
+// Option configures the database returned by New.
+type Option func(*DB) error
+
+// WithCodec makes New use codec to encode and decode documents, instead
+// of the default msgpack codec. New checks whichever codec ends up
+// selected against the engine's metadata once every option has been
+// applied, so reopening an existing database with a different codec -
+// including falling back to the default msgpack one - returns an error
+// rather than silently returning garbage documents.
+func WithCodec(codec encoding.Codec) Option {
+	return func(db *DB) error {
+		db.codec = codec
+		return nil
+	}
+}
@@ -1,20 +1,35 @@
 package parser
 
 import (
-	"github.com/asdine/genji/query"
-	"github.com/asdine/genji/query/scanner"
+	"github.com/genjidb/genji/query"
+	"github.com/genjidb/genji/sql/scanner"
 )
 
-// parseCreateStatement parses a create string and returns a query.Statement AST object.
-// This function assumes the CREATE token has already been consumed.
-func (p *Parser) parseCreateStatement() (query.CreateTableStmt, error) {
-	var stmt query.CreateTableStmt
+// parseCreateStatement parses a create string and returns a query.Statement
+// AST object. This function assumes the CREATE token has already been
+// consumed, and dispatches on the next token to the statement-specific
+// parser.
+func (p *Parser) parseCreateStatement() (query.Statement, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
 
-	// Parse "TABLE".
-	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.TABLE {
-		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE"}, pos)
+	switch tok {
+	case scanner.TABLE:
+		return p.parseCreateTableStatement()
+	case scanner.FULLTEXT:
+		return p.parseCreateFullTextIndexStatement()
+	case scanner.INDEX:
+		return p.parseCreateIndexStatement()
 	}
 
+	return nil, newParseError(scanner.Tokstr(tok, lit), []string{"TABLE", "INDEX", "FULLTEXT"}, pos)
+}
+
+// parseCreateTableStatement parses a create table string and returns a
+// query.CreateTableStmt AST object. This function assumes the CREATE and
+// TABLE tokens have already been consumed.
+func (p *Parser) parseCreateTableStatement() (query.CreateTableStmt, error) {
+	var stmt query.CreateTableStmt
+
 	// Parse table name
 	tableName, err := p.ParseIdent()
 	if err != nil {
@@ -42,3 +57,149 @@ func (p *Parser) parseCreateStatement() (query.CreateTableStmt, error) {
 
 	return stmt, nil
 }
+
+// parseCreateFullTextIndexStatement parses a create fulltext index string
+// and returns a query.CreateFullTextIndexStmt AST object. This function
+// assumes the CREATE and FULLTEXT tokens have already been consumed.
+func (p *Parser) parseCreateFullTextIndexStatement() (query.CreateFullTextIndexStmt, error) {
+	var stmt query.CreateFullTextIndexStmt
+
+	// Parse "INDEX".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.INDEX {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"INDEX"}, pos)
+	}
+
+	// Parse index name.
+	indexName, err := p.ParseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse "ON".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.ON {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Parse table name.
+	tableName, err := p.ParseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse "(".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.LPAREN {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"("}, pos)
+	}
+
+	// Parse indexed path.
+	path, err := p.ParseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse ")".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.RPAREN {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{")"}, pos)
+	}
+
+	stmt = query.CreateFullTextIndex(indexName, tableName, path)
+
+	return stmt, nil
+}
+
+// parseCreateIndexStatement parses a create index string and returns a
+// query.CreateIndexStmt AST object. This function assumes the CREATE
+// and INDEX tokens have already been consumed.
+func (p *Parser) parseCreateIndexStatement() (query.CreateIndexStmt, error) {
+	var stmt query.CreateIndexStmt
+
+	// Parse index name.
+	indexName, err := p.ParseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse "ON".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.ON {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Parse table name.
+	tableName, err := p.ParseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse "(".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.LPAREN {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{"("}, pos)
+	}
+
+	// Parse indexed path.
+	path, err := p.ParseIdent()
+	if err != nil {
+		return stmt, err
+	}
+
+	// Parse ")".
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.RPAREN {
+		return stmt, newParseError(scanner.Tokstr(tok, lit), []string{")"}, pos)
+	}
+
+	stmt = query.CreateIndex(indexName, tableName, path)
+
+	// Parse "WITH", optional.
+	async, err := p.parseWithAsyncOption()
+	if err != nil {
+		return stmt, err
+	}
+	if async {
+		stmt = stmt.Async()
+	}
+
+	return stmt, nil
+}
+
+// parseWithAsyncOption parses an optional "WITH (async=true)" clause
+// and reports whether async was set to true. Any other option found in
+// the WITH clause is currently rejected, since CREATE INDEX only
+// supports async today.
+func (p *Parser) parseWithAsyncOption() (bool, error) {
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != scanner.WITH {
+		p.Unscan()
+		return false, nil
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.LPAREN {
+		return false, newParseError(scanner.Tokstr(tok, lit), []string{"("}, pos)
+	}
+
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return false, err
+	}
+	if ident != "async" {
+		return false, newParseError(ident, []string{"async"}, 0)
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.EQ {
+		return false, newParseError(scanner.Tokstr(tok, lit), []string{"="}, pos)
+	}
+
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	var async bool
+	switch tok {
+	case scanner.TRUE:
+		async = true
+	case scanner.FALSE:
+		async = false
+	default:
+		return false, newParseError(scanner.Tokstr(tok, lit), []string{"true", "false"}, pos)
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != scanner.RPAREN {
+		return false, newParseError(scanner.Tokstr(tok, lit), []string{")"}, pos)
+	}
+
+	return async, nil
+}
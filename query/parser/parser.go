@@ -0,0 +1,77 @@
+// Package parser turns SQL text into query.Statement values, using
+// sql/scanner to tokenize the input.
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/genjidb/genji/sql/scanner"
+)
+
+// Parser parses SQL statements out of a reader, one token at a time.
+type Parser struct {
+	s *scanner.Scanner
+
+	buf struct {
+		tok     scanner.Token
+		pos     int
+		lit     string
+		scanned bool
+		unscan  bool
+	}
+}
+
+// NewParser creates a Parser reading from r.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{s: scanner.NewScanner(r)}
+}
+
+// NewParserFromString creates a Parser reading from s.
+func NewParserFromString(s string) *Parser {
+	return NewParser(strings.NewReader(s))
+}
+
+// Scan returns the next token, without skipping whitespace.
+func (p *Parser) Scan() (tok scanner.Token, pos int, lit string) {
+	if p.buf.unscan {
+		p.buf.unscan = false
+		return p.buf.tok, p.buf.pos, p.buf.lit
+	}
+
+	tok, pos, lit = p.s.Scan()
+	p.buf.tok, p.buf.pos, p.buf.lit = tok, pos, lit
+	return
+}
+
+// ScanIgnoreWhitespace returns the next non-whitespace token.
+func (p *Parser) ScanIgnoreWhitespace() (tok scanner.Token, pos int, lit string) {
+	for {
+		tok, pos, lit = p.Scan()
+		if tok != scanner.WS {
+			return
+		}
+	}
+}
+
+// Unscan pushes the last scanned token back, so the next call to Scan or
+// ScanIgnoreWhitespace returns it again.
+func (p *Parser) Unscan() {
+	p.buf.unscan = true
+}
+
+// ParseIdent parses the next token as an identifier.
+func (p *Parser) ParseIdent() (string, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != scanner.IDENT {
+		return "", newParseError(scanner.Tokstr(tok, lit), []string{"identifier"}, pos)
+	}
+	return lit, nil
+}
+
+// newParseError returns a human-readable error reporting that found was
+// scanned where one of expected was required.
+func newParseError(found string, expected []string, pos int) error {
+	return fmt.Errorf("found %q, expected %s at position %d", found, strings.Join(expected, ", "), pos)
+}
@@ -0,0 +1,68 @@
+// Package query defines the statement AST produced by query/parser and
+// consumed by the query planner.
+package query
+
+// Statement is a parsed SQL statement, ready to be planned and run
+// against a database.Transaction.
+type Statement interface {
+	isStatement()
+}
+
+// CreateTableStmt represents a parsed CREATE TABLE statement.
+type CreateTableStmt struct {
+	TableName       string
+	IfNotExistsFlag bool
+}
+
+func (CreateTableStmt) isStatement() {}
+
+// CreateTable creates a CreateTableStmt for the given table.
+func CreateTable(tableName string) CreateTableStmt {
+	return CreateTableStmt{TableName: tableName}
+}
+
+// IfNotExists marks the statement as tolerant of the table already
+// existing.
+func (stmt CreateTableStmt) IfNotExists() CreateTableStmt {
+	stmt.IfNotExistsFlag = true
+	return stmt
+}
+
+// CreateIndexStmt represents a parsed CREATE INDEX statement.
+type CreateIndexStmt struct {
+	IndexName string
+	TableName string
+	Path      string
+	AsyncFlag bool
+}
+
+func (CreateIndexStmt) isStatement() {}
+
+// CreateIndex creates a CreateIndexStmt for the given index.
+func CreateIndex(indexName, tableName, path string) CreateIndexStmt {
+	return CreateIndexStmt{IndexName: indexName, TableName: tableName, Path: path}
+}
+
+// Async marks the statement as a CREATE INDEX ... WITH (async=true): the
+// index is built in the background by database.IndexBuilder instead of
+// blocking the statement on a synchronous backfill.
+func (stmt CreateIndexStmt) Async() CreateIndexStmt {
+	stmt.AsyncFlag = true
+	return stmt
+}
+
+// CreateFullTextIndexStmt represents a parsed CREATE FULLTEXT INDEX
+// statement.
+type CreateFullTextIndexStmt struct {
+	IndexName string
+	TableName string
+	Path      string
+}
+
+func (CreateFullTextIndexStmt) isStatement() {}
+
+// CreateFullTextIndex creates a CreateFullTextIndexStmt for the given
+// full-text index.
+func CreateFullTextIndex(indexName, tableName, path string) CreateFullTextIndexStmt {
+	return CreateFullTextIndexStmt{IndexName: indexName, TableName: tableName, Path: path}
+}
@@ -0,0 +1,198 @@
+// Package enginequeue implements a queue.Queue backed by the same
+// engine.Engine a genji database already uses for storage, so queued
+// work (such as pending index builds) survives a restart without
+// requiring a separate dependency.
+package enginequeue
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/genjidb/genji/engine"
+	"github.com/genjidb/genji/queue"
+)
+
+// storeName is the engine store used to persist queue items. Items are
+// keyed by a monotonically increasing uint64 so that Pop always reads
+// them back in push order.
+const storeName = "__genji_queue"
+
+// Queue is a queue.Queue backed by an engine.Store. Push commits the
+// item to the store immediately so it is not lost on crash; Pop blocks
+// until either an item is available or the queue is closed.
+type Queue struct {
+	ng engine.Engine
+
+	notify    chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// New opens (or creates) the queue backed by ng.
+func New(ng engine.Engine) (*Queue, error) {
+	tx, err := ng.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.GetStore([]byte(storeName))
+	if err != nil {
+		if err := tx.CreateStore([]byte(storeName)); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &Queue{
+		ng:     ng,
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}, nil
+}
+
+var _ queue.Queue = (*Queue)(nil)
+
+func encodeKey(n uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return buf[:]
+}
+
+// Push implements the queue.Queue interface.
+func (q *Queue) Push(item []byte) error {
+	tx, err := q.ng.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	st, err := tx.GetStore([]byte(storeName))
+	if err != nil {
+		return err
+	}
+
+	n, err := nextKey(st)
+	if err != nil {
+		return err
+	}
+
+	if err := st.Put(encodeKey(n), item); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Pop implements the queue.Queue interface.
+func (q *Queue) Pop() ([]byte, error) {
+	for {
+		item, ok, err := q.popOnce()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return item, nil
+		}
+
+		select {
+		case <-q.notify:
+		case <-q.closed:
+			return nil, queue.ErrClosed
+		}
+	}
+}
+
+func (q *Queue) popOnce() (item []byte, ok bool, err error) {
+	tx, err := q.ng.Begin(true)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	st, err := tx.GetStore([]byte(storeName))
+	if err != nil {
+		return nil, false, err
+	}
+
+	it := st.NewIterator(engine.IteratorOptions{})
+	defer it.Close()
+
+	it.Seek(nil)
+	if !it.Valid() {
+		return nil, false, nil
+	}
+
+	key := it.Item().Key()
+	item, err = it.Item().ValueCopy(nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := st.Delete(key); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return item, true, nil
+}
+
+// Len implements the queue.Queue interface.
+func (q *Queue) Len() (int, error) {
+	tx, err := q.ng.Begin(false)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	st, err := tx.GetStore([]byte(storeName))
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	it := st.NewIterator(engine.IteratorOptions{})
+	defer it.Close()
+
+	for it.Seek(nil); it.Valid(); it.Next() {
+		n++
+	}
+
+	return n, nil
+}
+
+// Close implements the queue.Queue interface.
+func (q *Queue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+
+	return nil
+}
+
+func nextKey(st engine.Store) (uint64, error) {
+	it := st.NewIterator(engine.IteratorOptions{Reverse: true})
+	defer it.Close()
+
+	it.Seek(nil)
+	if !it.Valid() {
+		return 0, nil
+	}
+
+	return binary.BigEndian.Uint64(it.Item().Key()) + 1, nil
+}
@@ -0,0 +1,80 @@
+package enginequeue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/genjidb/genji/engine/memengine"
+	"github.com/genjidb/genji/queue"
+	"github.com/genjidb/genji/queue/enginequeue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineQueuePushPopOrder(t *testing.T) {
+	ng := memengine.NewEngine()
+
+	q, err := enginequeue.New(ng)
+	require.NoError(t, err)
+	defer q.Close()
+
+	require.NoError(t, q.Push([]byte("a")))
+	require.NoError(t, q.Push([]byte("b")))
+
+	n, err := q.Len()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	item, err := q.Pop()
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), item)
+
+	item, err = q.Pop()
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), item)
+}
+
+// TestEngineQueueSurvivesReopen is the property MemQueue deliberately
+// doesn't have: items pushed before a crash must still be there, in
+// order, when a new Queue is opened against the same engine.
+func TestEngineQueueSurvivesReopen(t *testing.T) {
+	ng := memengine.NewEngine()
+
+	q, err := enginequeue.New(ng)
+	require.NoError(t, err)
+	require.NoError(t, q.Push([]byte("a")))
+	require.NoError(t, q.Push([]byte("b")))
+	require.NoError(t, q.Close())
+
+	reopened, err := enginequeue.New(ng)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	n, err := reopened.Len()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	item, err := reopened.Pop()
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), item)
+}
+
+func TestEngineQueuePopBlocksUntilClose(t *testing.T) {
+	ng := memengine.NewEngine()
+	q, err := enginequeue.New(ng)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.Pop()
+		done <- err
+	}()
+
+	require.NoError(t, q.Close())
+
+	select {
+	case err := <-done:
+		require.Equal(t, queue.ErrClosed, err)
+	case <-time.After(time.Second):
+		t.Fatal("Pop did not return after Close")
+	}
+}
@@ -0,0 +1,58 @@
+package queue
+
+import "sync"
+
+// MemQueue is an in-memory Queue backed by a buffered channel. It does
+// not survive a process restart, which makes it a good fit for tests
+// that exercise the worker logic without needing a real engine.
+type MemQueue struct {
+	items chan []byte
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewMemQueue creates a MemQueue able to hold up to capacity items
+// without blocking Push.
+func NewMemQueue(capacity int) *MemQueue {
+	return &MemQueue{
+		items:  make(chan []byte, capacity),
+		closed: make(chan struct{}),
+	}
+}
+
+var _ Queue = (*MemQueue)(nil)
+
+// Push implements the Queue interface.
+func (q *MemQueue) Push(item []byte) error {
+	select {
+	case q.items <- item:
+		return nil
+	case <-q.closed:
+		return ErrClosed
+	}
+}
+
+// Pop implements the Queue interface.
+func (q *MemQueue) Pop() ([]byte, error) {
+	select {
+	case item := <-q.items:
+		return item, nil
+	case <-q.closed:
+		return nil, ErrClosed
+	}
+}
+
+// Len implements the Queue interface.
+func (q *MemQueue) Len() (int, error) {
+	return len(q.items), nil
+}
+
+// Close implements the Queue interface.
+func (q *MemQueue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/genjidb/genji/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemQueuePushPopOrder(t *testing.T) {
+	q := queue.NewMemQueue(10)
+	defer q.Close()
+
+	require.NoError(t, q.Push([]byte("a")))
+	require.NoError(t, q.Push([]byte("b")))
+
+	n, err := q.Len()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	item, err := q.Pop()
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), item)
+
+	item, err = q.Pop()
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), item)
+}
+
+func TestMemQueuePopBlocksUntilClose(t *testing.T) {
+	q := queue.NewMemQueue(1)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.Pop()
+		done <- err
+	}()
+
+	require.NoError(t, q.Close())
+
+	select {
+	case err := <-done:
+		require.Equal(t, queue.ErrClosed, err)
+	case <-time.After(time.Second):
+		t.Fatal("Pop did not return after Close")
+	}
+}
+
+func TestMemQueuePushAfterCloseFails(t *testing.T) {
+	// Unbuffered: with no reader ever running, the only way a Push can
+	// complete once closed is through the <-q.closed case becoming
+	// ready, so this isn't racing against the buffer having room.
+	q := queue.NewMemQueue(0)
+	require.NoError(t, q.Close())
+
+	err := q.Push([]byte("a"))
+	require.Equal(t, queue.ErrClosed, err)
+}
@@ -0,0 +1,31 @@
+// Package queue defines the Queue abstraction used to drive background
+// work, such as asynchronous index builds, behind a backend that can be
+// swapped between an in-memory implementation (used in tests) and a
+// disk-backed one (used in production so queued work survives restarts).
+package queue
+
+// Queue is a small durable work queue: items pushed to it are expected
+// to still be there, in order, after a process restart, so that a
+// worker resuming from Pop after a crash replays exactly the work that
+// was not yet acknowledged.
+type Queue interface {
+	// Push appends item to the back of the queue.
+	Push(item []byte) error
+	// Pop removes and returns the item at the front of the queue. It
+	// blocks until an item is available or the queue is closed, in
+	// which case it returns ErrClosed.
+	Pop() ([]byte, error)
+	// Len returns the number of items currently queued.
+	Len() (int, error)
+	// Close releases the resources held by the queue. A blocked Pop
+	// call returns ErrClosed.
+	Close() error
+}
+
+// ErrClosed is returned by Pop when the queue has been closed while a
+// call was blocked waiting for an item.
+var ErrClosed = errClosed{}
+
+type errClosed struct{}
+
+func (errClosed) Error() string { return "queue closed" }
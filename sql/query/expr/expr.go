@@ -0,0 +1,41 @@
+// Package expr defines the expression tree produced by the SQL parser
+// and evaluated during query execution, e.g. the filter carried by a
+// tree.indexInputNode or the search string of a MATCH query.
+package expr
+
+import "github.com/genjidb/genji/document"
+
+// Param represents a parameter passed by the user to a parameterized
+// query, bound positionally (?) or by name (e.g. $name).
+type Param struct {
+	Name  string
+	Value interface{}
+}
+
+// EvalStack carries the context an Expr is evaluated against: the
+// transaction it can read from and the parameters bound to the query.
+// Tx is declared as interface{} here, rather than *database.Transaction,
+// so that this package never has to import the database package.
+type EvalStack struct {
+	Tx     interface{}
+	Params []Param
+}
+
+// Expr is an expression that can be evaluated to a document.Value.
+type Expr interface {
+	Eval(EvalStack) (document.Value, error)
+	String() string
+}
+
+// LiteralValue is an Expr that always evaluates to the same value, e.g.
+// the search string on the right-hand side of a MATCH operator.
+type LiteralValue document.Value
+
+// Eval implements the Expr interface.
+func (v LiteralValue) Eval(EvalStack) (document.Value, error) {
+	return document.Value(v), nil
+}
+
+func (v LiteralValue) String() string {
+	return document.Value(v).V.(string)
+}
@@ -0,0 +1,42 @@
+package expr
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/document"
+)
+
+// MatchOperator represents the `path MATCH query` predicate recognized
+// by the parser for FULLTEXT INDEX lookups. The query planner is
+// expected to special-case it the way it already special-cases
+// comparison operators against a regular index: recognizing a
+// MatchOperator in a WHERE clause is what routes planning to
+// tree.PlanMatchInput, which builds a fullTextSearchInputNode instead of
+// a table scan or comparison index. See tree.PlanMatchInput's doc
+// comment for why that rule takes Path pre-resolved to a plain string
+// rather than pattern-matching on this type directly.
+type MatchOperator struct {
+	Path  Expr
+	Query Expr
+}
+
+// NewMatchOperator creates a MatchOperator matching path against query.
+func NewMatchOperator(path, query Expr) *MatchOperator {
+	return &MatchOperator{Path: path, Query: query}
+}
+
+func (op *MatchOperator) String() string {
+	return op.Path.String() + " MATCH " + op.Query.String()
+}
+
+// Eval implements the Expr interface so MatchOperator is a valid
+// building block for any WHERE-clause tree that types its nodes as
+// Expr. There is no document to run the search against here: the
+// EvalStack this method receives carries a transaction and parameters,
+// not the catalog lookup and bleve index tree.PlanMatchInput needs to
+// actually run a MATCH. Reaching this method means the planner failed
+// to recognize the MatchOperator and route around it, so it reports
+// that plainly instead of returning a meaningless boolean.
+func (op *MatchOperator) Eval(EvalStack) (document.Value, error) {
+	return document.Value{}, fmt.Errorf("expr: %s must be planned through tree.PlanMatchInput, not evaluated directly", op)
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+var keywords = map[string]Token{
+	"table":    TABLE,
+	"index":    INDEX,
+	"fulltext": FULLTEXT,
+	"match":    MATCH,
+	"if":       IF,
+	"not":      NOT,
+	"exists":   EXISTS,
+	"on":       ON,
+	"true":     TRUE,
+	"false":    FALSE,
+	"desc":     DESC,
+	"asc":      ASC,
+	"with":     WITH,
+}
+
+// Scanner turns a stream of runes into a stream of tokens.
+type Scanner struct {
+	r *bufio.Reader
+}
+
+// NewScanner creates a Scanner that reads from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r)}
+}
+
+const eof = rune(0)
+
+func (s *Scanner) read() rune {
+	ch, _, err := s.r.ReadRune()
+	if err != nil {
+		return eof
+	}
+	return ch
+}
+
+func (s *Scanner) unread() {
+	_ = s.r.UnreadRune()
+}
+
+func isWhitespace(ch rune) bool { return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' }
+func isLetter(ch rune) bool     { return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '_' }
+func isDigit(ch rune) bool      { return ch >= '0' && ch <= '9' }
+
+// Scan returns the next token, its position (byte offset) and literal.
+func (s *Scanner) Scan() (tok Token, pos int, lit string) {
+	ch := s.read()
+
+	switch {
+	case ch == eof:
+		return EOF, 0, ""
+	case isWhitespace(ch):
+		s.unread()
+		return s.scanWhitespace()
+	case isLetter(ch):
+		s.unread()
+		return s.scanIdent()
+	case ch == '(':
+		return LPAREN, 0, "("
+	case ch == ')':
+		return RPAREN, 0, ")"
+	case ch == '=':
+		return EQ, 0, "="
+	}
+
+	return ILLEGAL, 0, string(ch)
+}
+
+func (s *Scanner) scanWhitespace() (Token, int, string) {
+	var b strings.Builder
+	for {
+		ch := s.read()
+		if ch == eof {
+			break
+		}
+		if !isWhitespace(ch) {
+			s.unread()
+			break
+		}
+		b.WriteRune(ch)
+	}
+	return WS, 0, b.String()
+}
+
+func (s *Scanner) scanIdent() (Token, int, string) {
+	var b strings.Builder
+	for {
+		ch := s.read()
+		if ch == eof {
+			break
+		}
+		if !isLetter(ch) && !isDigit(ch) {
+			s.unread()
+			break
+		}
+		b.WriteRune(ch)
+	}
+
+	lit := b.String()
+	if tok, ok := keywords[strings.ToLower(lit)]; ok {
+		return tok, 0, lit
+	}
+	return IDENT, 0, lit
+}
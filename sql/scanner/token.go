@@ -0,0 +1,80 @@
+// Package scanner implements the lexer used by query/parser to turn SQL
+// text into a stream of tokens.
+package scanner
+
+import "fmt"
+
+// Token represents a lexical token of the SQL language.
+type Token int
+
+// Special tokens.
+const (
+	ILLEGAL Token = iota
+	EOF
+	WS
+	IDENT
+)
+
+// Keywords and symbols used by the statements this package's callers
+// parse. This is not an exhaustive SQL token set - it only covers what
+// query/parser currently needs.
+const (
+	TABLE Token = iota + 100
+	INDEX
+	FULLTEXT
+	MATCH
+	IF
+	NOT
+	EXISTS
+	ON
+	TRUE
+	FALSE
+	DESC
+	ASC
+	WITH
+
+	LPAREN
+	RPAREN
+	EQ
+)
+
+var tokens = map[Token]string{
+	ILLEGAL:  "ILLEGAL",
+	EOF:      "EOF",
+	WS:       "WS",
+	IDENT:    "IDENT",
+	TABLE:    "TABLE",
+	INDEX:    "INDEX",
+	FULLTEXT: "FULLTEXT",
+	MATCH:    "MATCH",
+	IF:       "IF",
+	NOT:      "NOT",
+	EXISTS:   "EXISTS",
+	ON:       "ON",
+	TRUE:     "TRUE",
+	FALSE:    "FALSE",
+	DESC:     "DESC",
+	ASC:      "ASC",
+	WITH:     "WITH",
+	LPAREN:   "(",
+	RPAREN:   ")",
+	EQ:       "=",
+}
+
+// String returns the string representation of the token.
+func (t Token) String() string {
+	if s, ok := tokens[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Token(%d)", int(t))
+}
+
+// Tokstr returns a readable string of tok, preferring lit (the literal
+// scanned from the source) when tok is IDENT, since "IDENT" on its own
+// isn't useful in a parse error message.
+func Tokstr(tok Token, lit string) string {
+	if tok == IDENT && lit != "" {
+		return lit
+	}
+	return tok.String()
+}
@@ -0,0 +1,111 @@
+package tree
+
+import (
+	"context"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/document"
+	"github.com/genjidb/genji/sql/query/expr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fullTextSearchInputNode is an input node that reads documents by
+// running a MATCH query against a FULLTEXT INDEX rather than scanning
+// the table or probing a comparison index.
+type fullTextSearchInputNode struct {
+	node
+
+	tableName string
+	indexName string
+	query     expr.Expr
+}
+
+// NewFullTextSearchInputNode creates a node that reads documents using a
+// full-text index. query is evaluated once, at iteration time, to
+// produce the search string.
+//
+// Exported so PlanMatchInput can construct it once it has resolved which
+// FULLTEXT INDEX a MATCH predicate should run against, the same way
+// NewTableInputNode and newIndexInputNode are constructed for the other
+// input shapes.
+func NewFullTextSearchInputNode(tableName, indexName string, query expr.Expr) Node {
+	return &fullTextSearchInputNode{
+		node: node{
+			op: Input,
+		},
+		tableName: tableName,
+		indexName: indexName,
+		query:     query,
+	}
+}
+
+func (i *fullTextSearchInputNode) ToStream(ctx context.Context, tx *database.Transaction, params []expr.Param) (document.Stream, error) {
+	ctx, span := tracerFromContext(ctx).Start(ctx, "FullTextInput", trace.WithAttributes(
+		attribute.String("table", i.tableName),
+		attribute.String("index", i.indexName),
+		attribute.String("filter", i.query.String()),
+	))
+
+	tb, err := tx.GetTable(i.tableName)
+	if err != nil {
+		span.End()
+		return document.Stream{}, err
+	}
+
+	idx, err := tx.GetFullTextIndex(i.indexName)
+	if err != nil {
+		span.End()
+		return document.Stream{}, err
+	}
+
+	return document.NewStream(&fullTextIterator{
+		ctx:    ctx,
+		tb:     tb,
+		params: params,
+		index:  idx,
+		query:  i.query,
+	}), nil
+}
+
+type fullTextIterator struct {
+	ctx    context.Context
+	tb     *database.Table
+	params []expr.Param
+	index  interface {
+		Search(query string, fn func(key []byte, score float64) error) error
+	}
+	query expr.Expr
+}
+
+func (it *fullTextIterator) Iterate(fn func(d document.Document) error) error {
+	span := trace.SpanFromContext(it.ctx)
+	defer span.End()
+
+	var count int
+	defer func() {
+		span.AddEvent("rows produced", trace.WithAttributes(attribute.Int("count", count)))
+	}()
+
+	v, err := it.query.Eval(expr.EvalStack{
+		Params: it.params,
+	})
+	if err != nil {
+		return err
+	}
+
+	q, err := v.ConvertToText()
+	if err != nil {
+		return err
+	}
+
+	return it.index.Search(q, func(key []byte, score float64) error {
+		d, err := it.tb.GetDocument(key)
+		if err != nil {
+			return err
+		}
+
+		count++
+		return fn(d)
+	})
+}
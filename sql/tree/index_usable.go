@@ -0,0 +1,17 @@
+package tree
+
+import "github.com/genjidb/genji/database"
+
+// IsIndexUsable reports whether indexName can be planned against. An
+// index that is still being backfilled asynchronously (CREATE INDEX ...
+// WITH (async=true)) is not usable yet: the optimizer must call this
+// before calling newIndexInputNode and fall back to a table scan if it
+// returns false.
+func IsIndexUsable(tx *database.Transaction, indexName string) (bool, error) {
+	cfg, err := tx.Catalog.GetIndexConfig(indexName)
+	if err != nil {
+		return false, err
+	}
+
+	return cfg.BuildStatus == database.IndexReady, nil
+}
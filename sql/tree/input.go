@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"context"
 	"errors"
 
 	"github.com/genjidb/genji/database"
@@ -8,6 +9,8 @@ import (
 	"github.com/genjidb/genji/index"
 	"github.com/genjidb/genji/sql/query/expr"
 	"github.com/genjidb/genji/sql/scanner"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type tableInputNode struct {
@@ -27,13 +30,57 @@ func NewTableInputNode(tableName string) Node {
 	}
 }
 
-func (i *tableInputNode) ToStream(tx *database.Transaction, params []expr.Param) (document.Stream, error) {
+func (i *tableInputNode) ToStream(ctx context.Context, tx *database.Transaction, params []expr.Param) (document.Stream, error) {
+	ctx, span := tracerFromContext(ctx).Start(ctx, "TableInput", trace.WithAttributes(
+		attribute.String("table", i.tableName),
+	))
+
 	tb, err := tx.GetTable(i.tableName)
 	if err != nil {
+		span.End()
 		return document.Stream{}, err
 	}
 
-	return document.NewStream(tb), nil
+	return document.NewStream(&tableIterator{
+		ctx: ctx,
+		tb:  tb,
+	}), nil
+}
+
+// tableIterator wraps a *database.Table so a full table scan reports a
+// "rows produced" span event the same way indexIterator and
+// fullTextIterator do for their own input nodes, instead of leaving table
+// scans - the one input shape the request cited as its motivating example
+// - as the only one whose span duration stops at the initial GetTable
+// lookup and never reflects how many rows were actually read.
+//
+// The span is ended here, in Iterate, rather than by a defer in ToStream:
+// ToStream returns the Stream well before the executor ever calls
+// Iterate, so ending the span there would close it before "rows
+// produced" could be attached, and OpenTelemetry treats recordings on an
+// already-ended span as no-ops. This does mean a Stream that is built but
+// never iterated leaks its span; document.Iterator has no Close to hook
+// a fallback into, and every Node.ToStream in this package already
+// assumes its Stream gets iterated exactly once, so this follows that
+// same assumption rather than introducing a new contract on its own.
+type tableIterator struct {
+	ctx context.Context
+	tb  *database.Table
+}
+
+func (it *tableIterator) Iterate(fn func(d document.Document) error) error {
+	span := trace.SpanFromContext(it.ctx)
+	defer span.End()
+
+	var count int
+	defer func() {
+		span.AddEvent("rows produced", trace.WithAttributes(attribute.Int("count", count)))
+	}()
+
+	return it.tb.Iterate(func(d document.Document) error {
+		count++
+		return fn(d)
+	})
 }
 
 type indexInputNode struct {
@@ -60,23 +107,55 @@ func newIndexInputNode(tableName, indexName string, iop indexIteratorOperator, f
 	}
 }
 
-func (i *indexInputNode) ToStream(tx *database.Transaction, params []expr.Param) (document.Stream, error) {
+func (i *indexInputNode) ToStream(ctx context.Context, tx *database.Transaction, params []expr.Param) (document.Stream, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("table", i.tableName),
+		attribute.String("index", i.indexName),
+	}
+	if i.e != nil {
+		attrs = append(attrs, attribute.String("filter", i.e.String()))
+	}
+
+	ctx, span := tracerFromContext(ctx).Start(ctx, "IndexInput", trace.WithAttributes(attrs...))
+
 	tb, err := tx.GetTable(i.tableName)
 	if err != nil {
+		span.End()
 		return document.Stream{}, err
 	}
 
 	idx, err := tx.GetIndex(i.indexName)
 	if err != nil {
+		span.End()
 		return document.Stream{}, err
 	}
 
+	usable, err := IsIndexUsable(tx, i.indexName)
+	if err != nil {
+		span.End()
+		return document.Stream{}, err
+	}
+	if !usable {
+		// The index is still being backfilled asynchronously: there is
+		// no optimizer in this tree to route around it ahead of time,
+		// so fall back to a table scan here instead of surfacing the
+		// state of the build to the caller as an error. This span ends
+		// here rather than wrapping an iterator: the stream it returns
+		// carries tableInputNode's own span instead.
+		span.AddEvent("index not usable, falling back to table scan")
+		span.End()
+		return NewTableInputNode(i.tableName).ToStream(ctx, tx, params)
+	}
+
 	return document.NewStream(&indexIterator{
-		tx:     tx,
-		tb:     tb,
-		params: params,
-		index:  idx,
-		e:      i.e,
+		ctx:              ctx,
+		tx:               tx,
+		tb:               tb,
+		params:           params,
+		index:            idx,
+		iop:              i.iop,
+		e:                i.e,
+		orderByDirection: i.orderByDirection,
 	}), nil
 }
 
@@ -85,6 +164,7 @@ type indexIteratorOperator interface {
 }
 
 type indexIterator struct {
+	ctx              context.Context
 	tx               *database.Transaction
 	tb               *database.Table
 	params           []expr.Param
@@ -97,6 +177,18 @@ type indexIterator struct {
 var errStop = errors.New("stop")
 
 func (it indexIterator) Iterate(fn func(d document.Document) error) error {
+	span := trace.SpanFromContext(it.ctx)
+	defer span.End()
+
+	var count int
+	countingFn := func(d document.Document) error {
+		count++
+		return fn(d)
+	}
+	defer func() {
+		span.AddEvent("rows produced", trace.WithAttributes(attribute.Int("count", count)))
+	}()
+
 	if it.e == nil {
 		var err error
 
@@ -107,7 +199,7 @@ func (it indexIterator) Iterate(fn func(d document.Document) error) error {
 					return err
 				}
 
-				return fn(r)
+				return countingFn(r)
 			})
 		} else {
 			err = it.index.AscendGreaterOrEqual(nil, func(val document.Value, key []byte) error {
@@ -116,7 +208,7 @@ func (it indexIterator) Iterate(fn func(d document.Document) error) error {
 					return err
 				}
 
-				return fn(r)
+				return countingFn(r)
 			})
 		}
 
@@ -138,5 +230,5 @@ func (it indexIterator) Iterate(fn func(d document.Document) error) error {
 		}
 	}
 
-	return it.iop.IterateIndex(it.index, it.tb, v, fn)
-}
\ No newline at end of file
+	return it.iop.IterateIndex(it.index, it.tb, v, countingFn)
+}
@@ -0,0 +1,38 @@
+package tree
+
+import (
+	"fmt"
+
+	"github.com/genjidb/genji/database"
+	"github.com/genjidb/genji/sql/query/expr"
+)
+
+// PlanMatchInput is this tree's one planner rule for the MATCH
+// predicate: given a path already recognized as the left-hand side of a
+// `path MATCH query` expression, it looks up the FULLTEXT INDEX covering
+// path on tableName and returns a node reading through it, instead of
+// falling back to a table scan.
+//
+// path is a plain string rather than the expr.MatchOperator itself (or
+// its Path field) because nothing in this tree parses a WHERE clause
+// into an expr.Expr yet: query/parser only ever parses CREATE
+// statements, there is no SELECT token, no string-literal scanning, and
+// no genji.DB.Exec/Query to run a planned statement against in the
+// first place. Once that SELECT/WHERE parser and a statement executor
+// exist, the caller recognizing an *expr.MatchOperator should extract
+// Path's literal column name and call this function exactly as it does
+// today; this rule itself does not need to change.
+func PlanMatchInput(tx *database.Transaction, tableName, path string, query expr.Expr) (Node, error) {
+	configs, err := tx.Catalog.ListFullTextIndexConfigs(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cfg := range configs {
+		if cfg.Path == path {
+			return NewFullTextSearchInputNode(tableName, cfg.IndexName, query), nil
+		}
+	}
+
+	return nil, fmt.Errorf("tree: no FULLTEXT INDEX on %s(%s)", tableName, path)
+}
@@ -0,0 +1,41 @@
+package genji
+
+import (
+	"context"
+
+	"github.com/genjidb/genji/sql/tree"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracerProvider sets the trace.TracerProvider used to instrument
+// query planning and execution. Once set, every Node.ToStream call and
+// every iterator in the sql/tree package opens a span under it, with
+// attributes for the table name, index name, iterator operator and
+// filter expression involved, plus an event reporting the number of
+// rows produced. If no provider is set, the global otel.TracerProvider
+// is used.
+func (db *DB) WithTracerProvider(tp trace.TracerProvider) *DB {
+	db.tracerProvider = tp
+	return db
+}
+
+// Context returns a copy of ctx carrying db's configured
+// trace.TracerProvider, falling back to the global one if
+// WithTracerProvider was never called. Query execution uses the
+// returned context so the spans opened in sql/tree land under the
+// provider configured on db instead of the process-wide default.
+func (db *DB) Context(ctx context.Context) context.Context {
+	return tree.ContextWithTracerProvider(ctx, db.tracerProviderOrGlobal())
+}
+
+// tracerProviderOrGlobal returns the TracerProvider configured on db,
+// falling back to the global one if none was set through
+// WithTracerProvider.
+func (db *DB) tracerProviderOrGlobal() trace.TracerProvider {
+	if db.tracerProvider == nil {
+		return otel.GetTracerProvider()
+	}
+
+	return db.tracerProvider
+}